@@ -28,15 +28,17 @@ func main() {
 	}
 
 	var groups []instrumentation.Group
+	var libraries []instrumentation.Library
 	groupsByRepo := make(map[string][]instrumentation.Group)
 
 	for _, repoInfo := range repoInfos {
-		scannedGroups, err := instrumentation.Scan(repoInfo.Name, repoInfo.Path)
+		scannedGroups, scannedLibraries, err := instrumentation.Scan(repoInfo.Name, repoInfo.Path)
 		if err != nil {
 			log.WithErrorMsg(err, "Error scanning instrumentation packages", "repo", repoInfo.Name)
 			continue
 		}
 		groups = append(groups, scannedGroups...)
+		libraries = append(libraries, scannedLibraries...)
 		groupsByRepo[repoInfo.Name] = scannedGroups
 	}
 
@@ -45,6 +47,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := instrumentation.GenerateLibraries(libraries); err != nil {
+		log.WithErrorMsg(err, "Error generating library summary")
+		os.Exit(1)
+	}
+
+	if err := instrumentation.GenerateInstrumentationList(libraries); err != nil {
+		log.WithErrorMsg(err, "Error generating instrumentation list")
+		os.Exit(1)
+	}
+
+	semconv, err := repo.NewRegistry().SemConv()
+	if err != nil {
+		log.WithErrorMsg(err, "Error resolving pinned semantic conventions")
+	} else if err := instrumentation.GenerateSemconvLag(libraries, semconv.Version()); err != nil {
+		log.WithErrorMsg(err, "Error generating semconv lag report")
+		os.Exit(1)
+	}
+
 	repoStats := instrumentation.CalculateStats(groupsByRepo)
 	for repoName, stats := range repoStats {
 		log.Info("Scan complete ✅",
@@ -3,11 +3,27 @@ package instrumentation
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
+	"golang.org/x/mod/semver"
+	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/packages"
 )
 
+const (
+	attributePkgPath = "go.opentelemetry.io/otel/attribute"
+	tracePkgPath     = "go.opentelemetry.io/otel/trace"
+	metricPkgPath    = "go.opentelemetry.io/otel/metric"
+	codesPkgPath     = "go.opentelemetry.io/otel/codes"
+)
+
 // AnalyzePackage performs static analysis on an instrumentation package.
 func AnalyzePackage(pkgPath string) (*PackageAnalysis, error) {
 	cfg := &packages.Config{
@@ -15,24 +31,38 @@ func AnalyzePackage(pkgPath string) (*PackageAnalysis, error) {
 			packages.NeedFiles |
 			packages.NeedCompiledGoFiles |
 			packages.NeedImports |
+			packages.NeedModule |
 			packages.NeedTypes |
 			packages.NeedSyntax |
 			packages.NeedTypesInfo,
 		Dir: pkgPath,
 	}
 
-	pkgs, err := packages.Load(cfg, ".")
+	// Load the whole module so helpers in sibling and internal packages
+	// can be followed from the instrumentation package.
+	pkgs, err := packages.Load(cfg, ".", "./...")
 	if err != nil {
 		return nil, err
 	}
 
-	if len(pkgs) == 0 {
+	units := libraryUnits(rootPackage(pkgs, pkgPath), pkgs)
+	if len(units) == 0 {
 		return nil, nil
 	}
+	pkg := units[0].pkg
+
+	var libraryPkgs []*packages.Package
+	for _, unit := range units {
+		libraryPkgs = append(libraryPkgs, unit.pkgs...)
+	}
 
-	pkg := pkgs[0]
+	resolver := newAttrResolver(libraryPkgs)
+	for _, unit := range units {
+		resolver.setOptionGuards(unit.pkg)
+	}
 	analysis := &PackageAnalysis{
-		Name: pkg.Name,
+		Name:       pkg.Name,
+		ImportPath: pkg.PkgPath,
 	}
 
 	// Extract package documentation
@@ -43,176 +73,470 @@ func AnalyzePackage(pkgPath string) (*PackageAnalysis, error) {
 		}
 	}
 
-	// Extract semantic conventions from imports
-	rawConventions := extractSemanticConventions(pkg)
-	analysis.SemanticConventions = mapSemanticConventions(rawConventions, pkg.PkgPath)
+	// Extract semantic convention versions from imports
+	analysis.SemconvVersions = extractSemconvVersions(pkg, libraryPkgs)
 
 	// Extract telemetry (spans, metrics) from tracer/meter usage
-	analysis.Telemetry = extractTelemetry(pkg)
-	analysis.Groups = convertTelemetryToGroups(pkg.PkgPath, analysis.Telemetry)
+	analysis.Telemetry, analysis.Groups = extractTelemetry(units, resolver)
+
+	// Extract configuration options (With* constructors)
+	analysis.Options = extractOptions(pkg)
+
+	// Extract instrumentation scopes created anywhere in the module
+	analysis.Scopes = extractScopes(libraryPkgs, resolver)
+
+	// Extract context propagation (Inject/Extract carriers, default propagator)
+	analysis.Propagation = extractPropagation(libraryPkgs, resolver, analysis.Options)
+
+	// Extract environment variables read anywhere in the module
+	analysis.EnvVars = extractEnvVars(libraryPkgs, resolver)
 
 	return analysis, nil
 }
 
-type PackageAnalysis struct {
-	Name                string
-	Description         string
-	SemanticConventions []string
-	Telemetry           []Telemetry
-	Groups              []Group
+// libraryUnit is a public package of the module together with the internal
+// packages whose telemetry is attributed to it.
+type libraryUnit struct {
+	pkg  *packages.Package
+	pkgs []*packages.Package
+	// unreachable holds the internal functions the public package never
+	// reaches, e.g. the client half of a shared internal/semconv package
+	// used by a server-only library. Only semconv helper instruments
+	// constructed in them are dropped.
+	unreachable map[*ast.FuncDecl]bool
 }
 
-func extractSemanticConventions(pkg *packages.Package) []string {
-	var conventions []string
-	seen := make(map[string]bool)
-
-	for _, imp := range pkg.Imports {
-		// Look for semconv imports
-		if strings.Contains(imp.PkgPath, "semconv") {
-			if !seen[imp.PkgPath] {
-				conventions = append(conventions, imp.PkgPath)
-				seen[imp.PkgPath] = true
-			}
+// inspect walks every file of the unit, passing the package it belongs to.
+func (u libraryUnit) inspect(fn func(n ast.Node, pkg *packages.Package) bool) {
+	for _, pkg := range u.pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				return fn(n, pkg)
+			})
 		}
 	}
+}
 
-	return conventions
+// reaches reports whether n lies outside the unit's unreachable functions.
+func (u libraryUnit) reaches(n ast.Node) bool {
+	for funcDecl := range u.unreachable {
+		if funcDecl.Pos() <= n.Pos() && n.End() <= funcDecl.End() {
+			return false
+		}
+	}
+	return true
 }
 
-func mapSemanticConventions(rawConventions []string, pkgPath string) []string {
-	var mapped []string
-	seen := make(map[string]bool)
+// markUnreachable records the functions of the unit's internal packages
+// that are not referenced, directly or through other internal functions,
+// from the public package or package-level declarations. Methods count as
+// reached once their receiver type is, as they may be called through an
+// interface.
+func (u *libraryUnit) markUnreachable() {
+	type pending struct {
+		node ast.Node
+		pkg  *packages.Package
+	}
 
-	for _, raw := range rawConventions {
-		conventions := inferConventionsFromImport(raw, pkgPath)
-		for _, conv := range conventions {
-			if !seen[conv] {
-				mapped = append(mapped, conv)
-				seen[conv] = true
+	funcDecls := make(map[types.Object]pending)
+	methods := make(map[types.Object][]pending)
+	var queue []pending
+	for _, pkg := range u.pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if pkg == u.pkg {
+				queue = append(queue, pending{file, pkg})
+				continue
+			}
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					queue = append(queue, pending{decl, pkg})
+					continue
+				}
+				obj := pkg.TypesInfo.Defs[funcDecl.Name]
+				if obj == nil {
+					continue
+				}
+				funcDecls[obj] = pending{funcDecl, pkg}
+				if recv := receiverTypeName(obj); recv != nil {
+					methods[recv] = append(methods[recv], pending{funcDecl, pkg})
+				}
 			}
 		}
 	}
 
-	if len(mapped) == 0 {
-		return rawConventions
+	reached := make(map[*ast.FuncDecl]bool)
+	reach := func(decl pending) {
+		if funcDecl := decl.node.(*ast.FuncDecl); !reached[funcDecl] {
+			reached[funcDecl] = true
+			queue = append(queue, decl)
+		}
+	}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		ast.Inspect(next.node, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj := next.pkg.TypesInfo.Uses[ident]
+			switch o := obj.(type) {
+			case *types.Func:
+				obj = o.Origin()
+			case *types.TypeName:
+				for _, method := range methods[o] {
+					reach(method)
+				}
+				delete(methods, o)
+			}
+			if decl, ok := funcDecls[obj]; ok {
+				reach(decl)
+			}
+			return true
+		})
 	}
 
-	return mapped
+	u.unreachable = make(map[*ast.FuncDecl]bool)
+	for _, decl := range funcDecls {
+		if funcDecl := decl.node.(*ast.FuncDecl); !reached[funcDecl] {
+			u.unreachable[funcDecl] = true
+		}
+	}
 }
 
-func inferConventionsFromImport(importPath string, pkgPath string) []string {
-	var conventions []string
+// receiverTypeName returns the type a method is declared on, or nil for
+// functions.
+func receiverTypeName(obj types.Object) types.Object {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Origin().Obj()
+	}
+	return nil
+}
 
-	pkgLower := strings.ToLower(pkgPath)
+// libraryUnits groups the module's library packages by the public package
+// their telemetry is attributed to, starting with root. Internal packages
+// belong to the first public package importing them; main, test and test
+// helper packages are skipped.
+func libraryUnits(root *packages.Package, pkgs []*packages.Package) []libraryUnit {
+	library := make(map[string]*packages.Package)
+	var public []*packages.Package
+	for _, pkg := range pkgs {
+		if !isLibraryPackage(pkg) {
+			continue
+		}
+		library[pkg.PkgPath] = pkg
+		if !isInternalPackage(pkg.PkgPath) {
+			public = append(public, pkg)
+		}
+	}
 
-	if strings.Contains(pkgLower, "http") ||
-		strings.Contains(pkgLower, "gin") ||
-		strings.Contains(pkgLower, "echo") ||
-		strings.Contains(pkgLower, "mux") ||
-		strings.Contains(pkgLower, "restful") {
-		if strings.Contains(pkgPath, "otelhttp") || strings.Contains(pkgPath, "httptrace") {
-			conventions = append(conventions, "HTTP_CLIENT_SPANS")
-		} else {
-			conventions = append(conventions, "HTTP_SERVER_SPANS")
+	sort.SliceStable(public, func(i, j int) bool {
+		if (public[i] == root) != (public[j] == root) {
+			return public[i] == root
 		}
-		conventions = append(conventions, "HTTP_SERVER_METRICS")
+		return public[i].PkgPath < public[j].PkgPath
+	})
+
+	owned := make(map[string]bool)
+	var units []libraryUnit
+	for _, pkg := range public {
+		unit := libraryUnit{pkg: pkg, pkgs: []*packages.Package{pkg}}
+		queue := []*packages.Package{pkg}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for path := range current.Imports {
+				internal, ok := library[path]
+				if !ok || !isInternalPackage(path) || owned[path] {
+					continue
+				}
+				owned[path] = true
+				unit.pkgs = append(unit.pkgs, internal)
+				queue = append(queue, internal)
+			}
+		}
+		unit.markUnreachable()
+		units = append(units, unit)
 	}
 
-	if strings.Contains(pkgLower, "grpc") {
-		conventions = append(conventions, "RPC_SERVER_SPANS")
-		conventions = append(conventions, "RPC_CLIENT_SPANS")
+	return units
+}
+
+// isLibraryPackage reports whether pkg is part of the instrumentation library
+// rather than an example program, a test-only package or a test helper.
+func isLibraryPackage(pkg *packages.Package) bool {
+	if pkg.Name == "" || pkg.Name == "main" || strings.HasSuffix(pkg.Name, "_test") || len(pkg.GoFiles) == 0 {
+		return false
 	}
+	_, testing := pkg.Imports["testing"]
+	return !testing
+}
+
+func isInternalPackage(pkgPath string) bool {
+	return strings.HasSuffix(pkgPath, "/internal") || strings.Contains(pkgPath, "/internal/")
+}
 
-	if strings.Contains(pkgLower, "mongo") || strings.Contains(pkgLower, "database") || strings.Contains(pkgLower, "sql") {
-		conventions = append(conventions, "DATABASE_CLIENT_SPANS")
+// rootPackage returns the package declared in dir, falling back to the first
+// loaded package.
+func rootPackage(pkgs []*packages.Package, dir string) *packages.Package {
+	if len(pkgs) == 0 {
+		return nil
 	}
 
-	if strings.Contains(pkgLower, "kafka") || strings.Contains(pkgLower, "messaging") {
-		conventions = append(conventions, "MESSAGING_CLIENT_SPANS")
+	absDir, err := filepath.Abs(dir)
+	if err == nil {
+		for _, pkg := range pkgs {
+			for _, file := range pkg.GoFiles {
+				if filepath.Dir(file) == absDir {
+					return pkg
+				}
+			}
+		}
 	}
 
-	if strings.Contains(pkgLower, "aws") || strings.Contains(pkgLower, "lambda") {
-		conventions = append(conventions, "FAAS_SPANS")
+	return pkgs[0]
+}
+
+type PackageAnalysis struct {
+	Name            string
+	ImportPath      string
+	Description     string
+	SemconvVersions []string
+	Scopes          []Scope
+	Propagation     *Propagation
+	Telemetry       []Telemetry
+	Groups          []Group
+	Options         []ConfigOption
+	EnvVars         []EnvVar
+}
+
+// extractSemconvVersions returns the semconv versions (e.g. v1.26.0) imported
+// by pkg or by the module packages it depends on, such as internal/semconv.
+func extractSemconvVersions(pkg *packages.Package, pkgs []*packages.Package) []string {
+	module := make(map[string]bool)
+	for _, p := range pkgs {
+		module[p.PkgPath] = true
 	}
 
-	if len(conventions) == 0 {
-		return []string{importPath}
+	var versions []string
+	seen := make(map[string]bool)
+	visited := map[string]bool{pkg.PkgPath: true}
+	queue := []*packages.Package{pkg}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, imp := range current.Imports {
+			if version := semconvVersion(imp.PkgPath); version != "" && !seen[version] {
+				versions = append(versions, version)
+				seen[version] = true
+			}
+			if module[imp.PkgPath] && !visited[imp.PkgPath] {
+				visited[imp.PkgPath] = true
+				queue = append(queue, imp)
+			}
+		}
 	}
 
-	return conventions
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versions[i], versions[j]) < 0
+	})
+
+	return versions
+}
+
+// semconvVersion returns the version element of a semconv import path,
+// e.g. go.opentelemetry.io/otel/semconv/v1.26.0/httpconv -> v1.26.0.
+func semconvVersion(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "semconv" && semver.IsValid(parts[i+1]) {
+			return parts[i+1]
+		}
+	}
+	return ""
 }
 
-func extractTelemetry(pkg *packages.Package) []Telemetry {
-	spans := extractSpans(pkg)
-	metrics := extractMetrics(pkg)
+// extractTelemetry extracts the spans and metrics of every unit, returning
+// the module's telemetry and the groups attributed to each public package.
+func extractTelemetry(units []libraryUnit, resolver *attrResolver) ([]Telemetry, []Group) {
+	var spans []Span
+	var metrics []Metric
+	var groups []Group
+
+	for _, unit := range units {
+		unitSpans := extractSpans(unit, resolver)
+		unitMetrics := extractMetrics(unit, resolver)
+		if len(unitSpans) == 0 && len(unitMetrics) == 0 {
+			continue
+		}
+
+		groups = append(groups, convertTelemetryToGroups(unit.pkg.PkgPath, splitTelemetry(unitSpans, unitMetrics))...)
+		spans = append(spans, unitSpans...)
+		metrics = append(metrics, unitMetrics...)
+	}
 
 	if len(spans) == 0 && len(metrics) == 0 {
-		return nil
+		return nil, groups
 	}
 
-	return []Telemetry{{
-		When:    "default",
-		Spans:   spans,
-		Metrics: metrics,
-	}}
+	return splitTelemetry(spans, metrics), groups
 }
 
 func isTracerStart(callExpr *ast.CallExpr, pkg *packages.Package) bool {
-	if len(callExpr.Args) < 1 {
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
 		return false
 	}
+	return receiverImplements(selExpr, pkg, tracePkgPath, "Tracer")
+}
 
-	firstArgType := pkg.TypesInfo.TypeOf(callExpr.Args[0])
-	if firstArgType == nil {
+// receiverImplements reports whether the receiver of a method call implements
+// the named interface declared in ifacePkgPath.
+func receiverImplements(selExpr *ast.SelectorExpr, pkg *packages.Package, ifacePkgPath, ifaceName string) bool {
+	if pkg.TypesInfo == nil {
 		return false
 	}
 
-	typeStr := firstArgType.String()
-	return strings.Contains(typeStr, "context.Context")
-}
+	selection, ok := pkg.TypesInfo.Selections[selExpr]
+	if !ok || selection.Kind() != types.MethodVal {
+		return false
+	}
 
-func extractSpans(pkg *packages.Package) []Span {
-	spanMap := make(map[SpanKind]*Span)
-	startCallCount := 0
+	ifacePkg := findImport(pkg.Types, ifacePkgPath)
+	if ifacePkg == nil {
+		return false
+	}
 
-	detectedKinds := detectSpanKindsInPackage(pkg)
+	obj := ifacePkg.Scope().Lookup(ifaceName)
+	if obj == nil {
+		return false
+	}
 
-	for _, file := range pkg.Syntax {
-		ast.Inspect(file, func(n ast.Node) bool {
-			callExpr, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
 
-			selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
+	recv := selection.Recv()
+	return types.Implements(recv, iface) || types.Implements(types.NewPointer(recv), iface)
+}
 
-			if selExpr.Sel.Name == "Start" && len(callExpr.Args) >= 2 && isTracerStart(callExpr, pkg) {
-				extractSpanFromStart(callExpr, spanMap, pkg.PkgPath, detectedKinds)
-				startCallCount++
-			}
+// findImport returns the package with the given path from the transitive
+// imports of pkg.
+func findImport(pkg *types.Package, path string) *types.Package {
+	if pkg == nil {
+		return nil
+	}
 
-			if selExpr.Sel.Name == "SetAttributes" {
-				extractSpanSetAttributes(callExpr, spanMap, detectedKinds)
+	seen := make(map[*types.Package]bool)
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.Path() == path {
+			return current
+		}
+		for _, imp := range current.Imports() {
+			if !seen[imp] {
+				seen[imp] = true
+				queue = append(queue, imp)
 			}
+		}
+	}
 
-			if selExpr.Sel.Name == "AddEvent" {
-				extractSpanAddEvent(callExpr, spanMap, detectedKinds)
+	return nil
+}
+
+// spanKey identifies a span by its name (or name template) and kind.
+type spanKey struct {
+	name string
+	kind SpanKind
+}
+
+func extractSpans(unit libraryUnit, resolver *attrResolver) []Span {
+	spanMap := make(map[spanKey]*Span)
+	spanVars := make(map[types.Object]*Span)
+
+	// First pass: every tracer.Start call becomes its own span, keyed by name.
+	spanBindings := make(map[*ast.CallExpr]ast.Expr)
+	unit.inspect(func(n ast.Node, pkg *packages.Package) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 && len(node.Lhs) == 2 {
+				if callExpr, ok := node.Rhs[0].(*ast.CallExpr); ok {
+					spanBindings[callExpr] = node.Lhs[1]
+				}
+			}
+		case *ast.CallExpr:
+			if !isStartCall(node, pkg) {
+				return true
 			}
+			span := extractSpanFromStart(node, spanMap, pkg, resolver)
+			if obj := identObject(spanBindings[node], pkg); obj != nil {
+				spanVars[obj] = span
+			}
+		}
+		return true
+	})
 
+	// Second pass: attributes added after span creation.
+	unit.inspect(func(n ast.Node, pkg *packages.Package) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
 			return true
-		})
-	}
+		}
+
+		selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
 
-	if startCallCount > 0 && len(spanMap) == 0 && len(detectedKinds) > 0 {
-		for kind := range detectedKinds {
-			spanMap[kind] = &Span{
-				Kind:       kind,
-				Attributes: getSemConvAttributesForSpan(kind, pkg.PkgPath),
+		targets := func() []*Span {
+			return spanTargets(selExpr.X, pkg, resolver, spanMap, spanVars)
+		}
+		switch selExpr.Sel.Name {
+		case "SetAttributes":
+			if receiverImplements(selExpr, pkg, tracePkgPath, "Span") {
+				extractSpanSetAttributes(callExpr, pkg, resolver, targets)
+			}
+		case "SetStatus":
+			if receiverImplements(selExpr, pkg, tracePkgPath, "Span") {
+				extractSpanStatus(callExpr, pkg, resolver, targets)
 			}
+		case "AddEvent", "RecordError":
+			if receiverImplements(selExpr, pkg, tracePkgPath, "Span") {
+				extractSpanEvent(callExpr, selExpr.Sel.Name, pkg, resolver, targets)
+			}
+		}
+
+		return true
+	})
+
+	// Spans are completed with the attributes of the semantic convention
+	// matching what they record.
+	namespaces := semconvNamespaces(unit)
+	for key, span := range spanMap {
+		if key == (spanKey{}) {
+			continue
 		}
+		span.Attributes = appendMissingAttributes(span.Attributes, semconvSpanAttributes(span.Kind, span.Attributes, namespaces)...)
 	}
 
 	var spans []Span
@@ -220,91 +544,271 @@ func extractSpans(pkg *packages.Package) []Span {
 		spans = append(spans, *span)
 	}
 
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Name != spans[j].Name {
+			return spans[i].Name < spans[j].Name
+		}
+		return spans[i].Kind < spans[j].Kind
+	})
+
 	return spans
 }
 
-func detectSpanKindsInPackage(pkg *packages.Package) map[SpanKind]bool {
-	kinds := make(map[SpanKind]bool)
+func isStartCall(callExpr *ast.CallExpr, pkg *packages.Package) bool {
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	return selExpr.Sel.Name == "Start" && len(callExpr.Args) >= 2 && isTracerStart(callExpr, pkg)
+}
 
-	for _, file := range pkg.Syntax {
-		ast.Inspect(file, func(n ast.Node) bool {
-			selExpr, ok := n.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
+// exprObject returns the variable or field an expression refers to.
+func exprObject(expr ast.Expr, pkg *packages.Package) types.Object {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return exprObject(e.X, pkg)
+	case *ast.SelectorExpr:
+		if pkg.TypesInfo == nil {
+			return nil
+		}
+		return pkg.TypesInfo.Uses[e.Sel]
+	default:
+		return identObject(expr, pkg)
+	}
+}
 
-			for _, kind := range []SpanKind{
-				SpanKindServer,
-				SpanKindClient,
-				SpanKindProducer,
-				SpanKindConsumer,
-			} {
-				if hasKind(selExpr.Sel.Name, kind) {
-					kinds[kind] = true
-				}
+// identObject resolves the object an identifier defines or refers to.
+func identObject(expr ast.Expr, pkg *packages.Package) types.Object {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ident.Name == "_" || pkg.TypesInfo == nil {
+		return nil
+	}
+	if obj := pkg.TypesInfo.Defs[ident]; obj != nil {
+		return obj
+	}
+	return pkg.TypesInfo.Uses[ident]
+}
+
+// spanTargets returns the spans a SetAttributes/AddEvent receiver refers to.
+// Receivers bound to a tracer.Start result map to that span, span parameters
+// to the spans passed at every call site. Receivers that cannot be traced
+// (e.g. trace.SpanFromContext) map to the unknown span.
+func spanTargets(recv ast.Expr, pkg *packages.Package, resolver *attrResolver, spanMap map[spanKey]*Span, spanVars map[types.Object]*Span) []*Span {
+	spans, ok := resolveSpans(recv, pkg, resolver, spanVars, make(map[types.Object]bool))
+	if !ok {
+		spans = append(spans, unknownSpan(spanMap))
+	}
+	return spans
+}
+
+// resolveSpans follows a span expression back to tracer.Start results,
+// reporting whether every path it takes could be traced.
+func resolveSpans(expr ast.Expr, pkg *packages.Package, resolver *attrResolver, spanVars map[types.Object]*Span, seen map[types.Object]bool) ([]*Span, bool) {
+	v, ok := identObject(expr, pkg).(*types.Var)
+	if !ok {
+		return nil, false
+	}
+	if span, ok := spanVars[v]; ok {
+		return []*Span{span}, true
+	}
+	if seen[v] {
+		return nil, true
+	}
+	seen[v] = true
+
+	fn, index, ok := resolver.paramOf(v)
+	if !ok {
+		return nil, false
+	}
+	calls := resolver.callSites(fn)
+	if len(calls) == 0 {
+		return nil, false
+	}
+
+	var spans []*Span
+	traced := true
+	for _, call := range calls {
+		if index >= len(call.call.Args) {
+			traced = false
+			continue
+		}
+		callSpans, ok := resolveSpans(call.call.Args[index], call.pkg, resolver, spanVars, seen)
+		spans = append(spans, callSpans...)
+		traced = traced && ok
+	}
+	return spans, traced
+}
+
+// unknownSpan returns the placeholder standing for spans started outside the
+// package, on which telemetry is recorded that cannot be traced to a
+// tracer.Start call.
+func unknownSpan(spanMap map[spanKey]*Span) *Span {
+	key := spanKey{}
+	span, ok := spanMap[key]
+	if !ok {
+		span = &Span{Attributes: []Attribute{}}
+		spanMap[key] = span
+	}
+	return span
+}
+
+// extractSpanName resolves the span name argument of tracer.Start. Constant
+// names are returned verbatim, fmt.Sprintf calls become templates and any
+// other expression is rendered as a {placeholder}.
+func extractSpanName(expr ast.Expr, pkg *packages.Package) string {
+	if pkg.TypesInfo != nil {
+		if tv, ok := pkg.TypesInfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value)
+		}
+	}
+
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		return strings.Trim(lit.Value, "`\"")
+	}
+
+	if callExpr, ok := expr.(*ast.CallExpr); ok {
+		if selExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok && selExpr.Sel.Name == "Sprintf" && len(callExpr.Args) > 0 {
+			if format := extractSpanName(callExpr.Args[0], pkg); !isSpanNamePlaceholder(format) {
+				return spanNameTemplate(format, callExpr.Args[1:])
 			}
-			return true
-		})
+		}
 	}
 
-	return kinds
+	return "{" + types.ExprString(expr) + "}"
+}
+
+func isSpanNamePlaceholder(name string) bool {
+	return strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}")
 }
 
-func hasKind(kindName string, kind SpanKind) bool {
-	kindStr := strings.ToLower(string(kind))
-	nameStr := strings.ToLower(kindName)
-	return strings.Contains(nameStr, kindStr)
+// spanNameTemplate substitutes the verbs of a format string with the
+// expressions passed for them, e.g. ("HTTP %s", method) -> "HTTP {method}".
+func spanNameTemplate(format string, args []ast.Expr) string {
+	var b strings.Builder
+	argIdx := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		if format[i+1] == '%' {
+			b.WriteByte('%')
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(format) && strings.ContainsRune("+-# 0123456789.*", rune(format[j])) {
+			j++
+		}
+		if argIdx < len(args) {
+			b.WriteString("{" + types.ExprString(args[argIdx]) + "}")
+		} else {
+			b.WriteString("{}")
+		}
+		argIdx++
+		i = j
+	}
+	return b.String()
 }
 
-func extractSpanFromStart(callExpr *ast.CallExpr, spanMap map[SpanKind]*Span, pkgPath string, detectedKinds map[SpanKind]bool) {
-	var spanKind SpanKind
+// extractSpanFromStart records the span started by a tracer.Start call. Spans
+// started without trace.WithSpanKind are internal, as in the OTel API.
+func extractSpanFromStart(callExpr *ast.CallExpr, spanMap map[spanKey]*Span, pkg *packages.Package, resolver *attrResolver) *Span {
+	spanKind := SpanKindInternal
 	var attributes []Attribute
 
 	if len(callExpr.Args) >= 3 {
 		for i := 2; i < len(callExpr.Args); i++ {
-			kind, attrs := parseSpanStartOption(callExpr.Args[i])
-			if kind != "" {
-				spanKind = kind
+			for _, opt := range resolver.expand(callExpr.Args[i], pkg) {
+				kind, attrs := parseSpanStartOption(opt.expr, opt.pkg, resolver)
+				if kind != "" {
+					spanKind = kind
+				}
+				for _, attr := range attrs {
+					attr.Start = true
+					attributes = append(attributes, attr)
+				}
 			}
-			attributes = append(attributes, attrs...)
 		}
 	}
 
-	if spanKind == "" {
-		if detectedKinds[SpanKindServer] {
-			spanKind = SpanKindServer
-		} else if detectedKinds[SpanKindClient] {
-			spanKind = SpanKindClient
-		} else if detectedKinds[SpanKindProducer] {
-			spanKind = SpanKindProducer
-		} else if detectedKinds[SpanKindConsumer] {
-			spanKind = SpanKindConsumer
-		} else {
-			spanKind = SpanKindInternal
+	key := spanKey{
+		name: extractSpanName(callExpr.Args[1], pkg),
+		kind: spanKind,
+	}
+	// Attributes guarded by the same condition as the span itself are
+	// recorded whenever the span is.
+	condition := enclosingCondition(callExpr, pkg)
+	for i := range attributes {
+		if attributes[i].Condition == condition {
+			attributes[i].Condition = ""
 		}
 	}
 
-	if _, exists := spanMap[spanKind]; !exists {
-		spanMap[spanKind] = &Span{
-			Kind:       spanKind,
-			Attributes: getSemConvAttributesForSpan(spanKind, pkgPath),
+	when := resolver.guard(callExpr, pkg)
+	span, exists := spanMap[key]
+	if !exists {
+		span = &Span{
+			Name:   key.name,
+			Kind:   spanKind,
+			Source: callSite(callExpr, pkg),
+			When:   when,
 		}
+		spanMap[key] = span
+	} else if when == "" {
+		span.When = ""
 	}
 
-	attrMap := make(map[string]bool)
-	for _, attr := range spanMap[spanKind].Attributes {
-		attrMap[attr.Name] = true
+	span.Attributes = mergeAttributes(span.Attributes, attributes)
+	return span
+}
+
+// callSite formats the position of a node as file.go:line.
+func callSite(node ast.Node, pkg *packages.Package) string {
+	if pkg.Fset == nil {
+		return ""
+	}
+	pos := pkg.Fset.Position(node.Pos())
+	// Files in subpackages, e.g. internal ones, are reported relative to the
+	// module directory.
+	file := filepath.Base(pos.Filename)
+	if pkg.Module != nil && pkg.Module.Dir != "" {
+		if rel, err := filepath.Rel(pkg.Module.Dir, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+		}
+	}
+	return fmt.Sprintf("%s:%d", file, pos.Line)
+}
+
+// mergeAttributes appends attributes not already present by name.
+func mergeAttributes(existing []Attribute, attributes []Attribute) []Attribute {
+	attrIndex := make(map[string]int)
+	for i, attr := range existing {
+		attrIndex[attr.Name] = i
 	}
 
 	for _, attr := range attributes {
-		if !attrMap[attr.Name] {
-			spanMap[spanKind].Attributes = append(spanMap[spanKind].Attributes, attr)
-			attrMap[attr.Name] = true
+		i, ok := attrIndex[attr.Name]
+		if !ok {
+			attrIndex[attr.Name] = len(existing)
+			existing = append(existing, attr)
+			continue
+		}
+		// Attributes recorded unconditionally anywhere are unconditional.
+		if attr.When == "" {
+			existing[i].When = ""
 		}
+		if attr.Condition == "" {
+			existing[i].Condition = ""
+		}
+		existing[i].Start = existing[i].Start || attr.Start
+		existing[i].Examples = mergeStrings(existing[i].Examples, attr.Examples)
+		existing[i].Enum = existing[i].Enum && attr.Enum
 	}
+	return existing
 }
 
-func parseSpanStartOption(expr ast.Expr) (SpanKind, []Attribute) {
+func parseSpanStartOption(expr ast.Expr, pkg *packages.Package, resolver *attrResolver) (SpanKind, []Attribute) {
 	callExpr, ok := expr.(*ast.CallExpr)
 	if !ok {
 		return "", nil
@@ -316,19 +820,49 @@ func parseSpanStartOption(expr ast.Expr) (SpanKind, []Attribute) {
 	}
 
 	if selExpr.Sel.Name == "WithSpanKind" && len(callExpr.Args) > 0 {
-		kind := extractSpanKind(callExpr.Args[0])
+		kind := extractSpanKind(callExpr.Args[0], pkg, resolver)
 		return kind, nil
 	}
 
 	if selExpr.Sel.Name == "WithAttributes" {
-		attrs := extractAttributes(callExpr.Args)
+		attrs := extractAttributes(callExpr.Args, pkg, resolver)
 		return "", attrs
 	}
 
 	return "", nil
 }
 
-func extractSpanKind(expr ast.Expr) SpanKind {
+// traceSpanKinds maps go.opentelemetry.io/otel/trace SpanKind values to
+// their names. SpanKindUnspecified is treated as internal by the API.
+var traceSpanKinds = map[int64]SpanKind{
+	0: SpanKindInternal,
+	1: SpanKindInternal,
+	2: SpanKindServer,
+	3: SpanKindClient,
+	4: SpanKindProducer,
+	5: SpanKindConsumer,
+}
+
+// extractSpanKind resolves the kind passed to trace.WithSpanKind, following
+// variables and helper functions back to the constant it is set from.
+func extractSpanKind(expr ast.Expr, pkg *packages.Package, resolver *attrResolver) SpanKind {
+	for _, leaf := range resolver.expand(expr, pkg) {
+		if kind := spanKindValue(leaf.expr, leaf.pkg); kind != "" {
+			return kind
+		}
+	}
+	return SpanKindInternal
+}
+
+func spanKindValue(expr ast.Expr, pkg *packages.Package) SpanKind {
+	if pkg.TypesInfo != nil {
+		if tv, ok := pkg.TypesInfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+			if value, ok := constant.Int64Val(tv.Value); ok {
+				return traceSpanKinds[value]
+			}
+		}
+	}
+
 	selExpr, ok := expr.(*ast.SelectorExpr)
 	if !ok {
 		return ""
@@ -344,28 +878,44 @@ func extractSpanKind(expr ast.Expr) SpanKind {
 		return SpanKindProducer
 	case strings.Contains(kindName, string(SpanKindConsumer)):
 		return SpanKindConsumer
-	default:
+	case strings.Contains(kindName, string(SpanKindInternal)):
 		return SpanKindInternal
+	default:
+		return ""
 	}
 }
 
-func extractAttributes(args []ast.Expr) []Attribute {
+func extractAttributes(args []ast.Expr, pkg *packages.Package, resolver *attrResolver) []Attribute {
 	var attributes []Attribute
 
 	for _, arg := range args {
-		attr := parseAttributeExpr(arg)
-		if attr.Name != "" {
-			attributes = append(attributes, attr)
+		attributes = append(attributes, resolver.attributes(arg, pkg)...)
+	}
+
+	if len(args) > 0 {
+		when := resolver.guard(args[0], pkg)
+		condition := enclosingCondition(args[0], pkg)
+		for i := range attributes {
+			if attributes[i].When == "" {
+				attributes[i].When = when
+			}
+			if attributes[i].Condition == "" {
+				attributes[i].Condition = condition
+			}
 		}
 	}
 
 	return attributes
 }
 
-func parseAttributeExpr(expr ast.Expr) Attribute {
+// parseAttributeExpr resolves an attribute.KeyValue expression to its key and
+// value type. It understands attribute.X(key, v) with literal or constant
+// keys, attribute.Key methods such as semconv.HTTPRequestMethodKey.String(v),
+// and semconv helpers such as semconv.HTTPRoute(r) or semconv.HTTPRequestMethodGet.
+func parseAttributeExpr(expr ast.Expr, pkg *packages.Package) Attribute {
 	callExpr, ok := expr.(*ast.CallExpr)
 	if !ok {
-		return Attribute{}
+		return parseSemconvKeyValue(expr, pkg)
 	}
 
 	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
@@ -373,30 +923,258 @@ func parseAttributeExpr(expr ast.Expr) Attribute {
 		return Attribute{}
 	}
 
+	if key, ok := attributeKey(selExpr.X, pkg); ok {
+		return Attribute{
+			Name: key,
+			Type: getAttributeType(selExpr.Sel.Name),
+		}
+	}
+
+	fn := calledFunc(selExpr, pkg)
+	if fn != nil && fn.Pkg() != nil && fn.Pkg().Path() != attributePkgPath {
+		return parseSemconvHelper(fn)
+	}
+
 	if len(callExpr.Args) < 2 {
 		return Attribute{}
 	}
 
-	keyLit, ok := callExpr.Args[0].(*ast.BasicLit)
+	attrName, ok := constantString(callExpr.Args[0], pkg)
 	if !ok {
 		return Attribute{}
 	}
 
-	attrName := strings.Trim(keyLit.Value, `"`)
-	attrType := getAttributeType(selExpr.Sel.Name)
-
 	return Attribute{
 		Name: attrName,
-		Type: attrType,
+		Type: getAttributeType(selExpr.Sel.Name),
 	}
 }
 
-func getAttributeType(funcName string) AttributeType {
-	switch {
-	case strings.Contains(funcName, "String"):
-		return AttributeTypeString
-	case strings.Contains(funcName, "Int64"), strings.Contains(funcName, "Int"):
-		return AttributeTypeLong
+// attributeValueExpr returns the value argument of an attribute constructor
+// accepted by parseAttributeExpr, e.g. v in attribute.String(key, v),
+// semconv.HTTPRequestMethodKey.String(v) or semconv.HTTPRoute(v).
+func attributeValueExpr(expr ast.Expr, pkg *packages.Package) ast.Expr {
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok || len(callExpr.Args) == 0 {
+		return nil
+	}
+
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := attributeKey(selExpr.X, pkg); ok {
+		return callExpr.Args[0]
+	}
+
+	fn := calledFunc(selExpr, pkg)
+	if fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == attributePkgPath && len(callExpr.Args) < 2 {
+		return nil
+	}
+
+	return callExpr.Args[len(callExpr.Args)-1]
+}
+
+// constantValue formats a constant expression of any basic kind.
+func constantValue(expr ast.Expr, pkg *packages.Package) (string, bool) {
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return "", false
+	}
+
+	switch tv.Value.Kind() {
+	case constant.String:
+		return constant.StringVal(tv.Value), true
+	case constant.Bool, constant.Int:
+		return tv.Value.ExactString(), true
+	case constant.Float:
+		f, _ := constant.Float64Val(tv.Value)
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	}
+
+	return "", false
+}
+
+// constantString resolves a string literal or constant expression.
+func constantString(expr ast.Expr, pkg *packages.Package) (string, bool) {
+	if pkg.TypesInfo != nil {
+		if tv, ok := pkg.TypesInfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value), true
+		}
+	}
+
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		return strings.Trim(lit.Value, "`\""), true
+	}
+
+	return "", false
+}
+
+// attributeKey resolves an attribute.Key valued expression such as
+// attribute.Key("x") or semconv.HTTPRequestMethodKey to its key string.
+func attributeKey(expr ast.Expr, pkg *packages.Package) (string, bool) {
+	if pkg.TypesInfo != nil {
+		if tv, ok := pkg.TypesInfo.Types[expr]; ok && isNamedType(tv.Type, attributePkgPath, "Key") {
+			return constantString(expr, pkg)
+		}
+	}
+
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok || len(callExpr.Args) != 1 {
+		return "", false
+	}
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok || selExpr.Sel.Name != "Key" {
+		return "", false
+	}
+	return constantString(callExpr.Args[0], pkg)
+}
+
+// calledFunc returns the function or method a selector call resolves to.
+func calledFunc(selExpr *ast.SelectorExpr, pkg *packages.Package) *types.Func {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	fn, _ := pkg.TypesInfo.Uses[selExpr.Sel].(*types.Func)
+	return fn
+}
+
+func isNamedType(t types.Type, pkgPath, name string) bool {
+	if t == nil {
+		return false
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+// parseSemconvHelper resolves a helper returning attribute.KeyValue, such as
+// semconv.HTTPRoute(route), via the matching <Name>Key constant declared
+// alongside it.
+func parseSemconvHelper(fn *types.Func) Attribute {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() != nil || sig.Results().Len() != 1 {
+		return Attribute{}
+	}
+	if !isNamedType(sig.Results().At(0).Type(), attributePkgPath, "KeyValue") {
+		return Attribute{}
+	}
+
+	key, ok := lookupSemconvKey(fn.Pkg(), fn.Name())
+	if !ok {
+		return Attribute{}
+	}
+
+	attrType := semconvAttributeType(key)
+	if sig.Params().Len() > 0 {
+		attrType = goAttributeType(sig.Params().At(sig.Params().Len() - 1).Type())
+	}
+
+	return Attribute{
+		Name: key,
+		Type: attrType,
+	}
+}
+
+// parseSemconvKeyValue resolves predefined attribute.KeyValue variables, such
+// as semconv.HTTPRequestMethodGet, to the key they are declared for.
+func parseSemconvKeyValue(expr ast.Expr, pkg *packages.Package) Attribute {
+	selExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok || pkg.TypesInfo == nil {
+		return Attribute{}
+	}
+
+	v, ok := pkg.TypesInfo.Uses[selExpr.Sel].(*types.Var)
+	if !ok || v.Pkg() == nil || !isNamedType(v.Type(), attributePkgPath, "KeyValue") {
+		return Attribute{}
+	}
+
+	key, ok := lookupSemconvKey(v.Pkg(), v.Name())
+	if !ok {
+		return Attribute{}
+	}
+
+	return Attribute{
+		Name: key,
+		Type: semconvAttributeType(key),
+	}
+}
+
+// lookupSemconvKey finds the longest <prefix>Key attribute.Key constant for a
+// semconv identifier, e.g. HTTPRequestMethodGet -> HTTPRequestMethodKey.
+func lookupSemconvKey(pkg *types.Package, name string) (string, bool) {
+	for i := len(name); i > 0; i-- {
+		if i < len(name) && !unicode.IsUpper(rune(name[i])) {
+			continue
+		}
+		c, ok := pkg.Scope().Lookup(name[:i] + "Key").(*types.Const)
+		if !ok || !isNamedType(c.Type(), attributePkgPath, "Key") || c.Val().Kind() != constant.String {
+			continue
+		}
+		return constant.StringVal(c.Val()), true
+	}
+	return "", false
+}
+
+func semconvAttributeType(key string) AttributeType {
+	if attr, ok := GetSemconvAttribute(key); ok && attr.Type != "" {
+		return AttributeType(attr.Type)
+	}
+	return AttributeTypeString
+}
+
+// goAttributeType maps a Go value type to its attribute type.
+func goAttributeType(t types.Type) AttributeType {
+	if slice, ok := t.Underlying().(*types.Slice); ok {
+		switch goAttributeType(slice.Elem()) {
+		case AttributeTypeLong:
+			return AttributeTypeLongArray
+		case AttributeTypeBoolean:
+			return AttributeTypeBooleanArray
+		case AttributeTypeDouble:
+			return AttributeTypeDoubleArray
+		default:
+			return AttributeTypeStringArray
+		}
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return AttributeTypeString
+	}
+	switch {
+	case basic.Info()&types.IsInteger != 0:
+		return AttributeTypeLong
+	case basic.Info()&types.IsFloat != 0:
+		return AttributeTypeDouble
+	case basic.Info()&types.IsBoolean != 0:
+		return AttributeTypeBoolean
+	default:
+		return AttributeTypeString
+	}
+}
+
+func getAttributeType(funcName string) AttributeType {
+	switch {
+	case strings.Contains(funcName, "StringSlice"):
+		return AttributeTypeStringArray
+	case strings.Contains(funcName, "Int64Slice"), strings.Contains(funcName, "IntSlice"):
+		return AttributeTypeLongArray
+	case strings.Contains(funcName, "BoolSlice"):
+		return AttributeTypeBooleanArray
+	case strings.Contains(funcName, "Float64Slice"):
+		return AttributeTypeDoubleArray
+	case strings.Contains(funcName, "String"):
+		return AttributeTypeString
+	case strings.Contains(funcName, "Int64"), strings.Contains(funcName, "Int"):
+		return AttributeTypeLong
 	case strings.Contains(funcName, "Bool"):
 		return AttributeTypeBoolean
 	case strings.Contains(funcName, "Float64"), strings.Contains(funcName, "Float"):
@@ -406,102 +1184,348 @@ func getAttributeType(funcName string) AttributeType {
 	}
 }
 
-func extractSpanSetAttributes(callExpr *ast.CallExpr, spanMap map[SpanKind]*Span, detectedKinds map[SpanKind]bool) {
-	attributes := extractAttributes(callExpr.Args)
+func extractSpanSetAttributes(callExpr *ast.CallExpr, pkg *packages.Package, resolver *attrResolver, targets func() []*Span) {
+	attributes := extractAttributes(callExpr.Args, pkg, resolver)
 	if len(attributes) == 0 {
 		return
 	}
 
-	if len(spanMap) == 0 {
-		for kind := range detectedKinds {
-			if _, exists := spanMap[kind]; !exists {
-				spanMap[kind] = &Span{
-					Kind:       kind,
-					Attributes: []Attribute{},
-				}
+	for _, span := range targets() {
+		span.Attributes = mergeAttributes(span.Attributes, attributes)
+	}
+}
+
+// exceptionAttributes are recorded by span.RecordError on the "exception" event.
+var exceptionAttributes = []Attribute{
+	{Name: "exception.type", Type: AttributeTypeString},
+	{Name: "exception.message", Type: AttributeTypeString},
+}
+
+// extractSpanEvent records the event emitted by span.AddEvent or
+// span.RecordError on each target span.
+func extractSpanEvent(callExpr *ast.CallExpr, method string, pkg *packages.Package, resolver *attrResolver, targets func() []*Span) {
+	if len(callExpr.Args) < 1 {
+		return
+	}
+
+	event := Event{Source: callSite(callExpr, pkg)}
+	if method == "RecordError" {
+		event.Name = "exception"
+		event.Attributes = append(event.Attributes, exceptionAttributes...)
+	} else {
+		event.Name = extractSpanName(callExpr.Args[0], pkg)
+	}
+	if semconvEvent, ok := GetSemconvEvent(event.Name); ok {
+		event.SemconvRef = semconvEvent.ID
+	}
+
+	for i := 1; i < len(callExpr.Args); i++ {
+		for _, opt := range resolver.expand(callExpr.Args[i], pkg) {
+			innerCall, ok := opt.expr.(*ast.CallExpr)
+			if !ok {
+				continue
 			}
-		}
-		if len(spanMap) == 0 {
-			spanMap[SpanKindInternal] = &Span{
-				Kind:       SpanKindInternal,
-				Attributes: []Attribute{},
+			selExpr, ok := innerCall.Fun.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			switch selExpr.Sel.Name {
+			case "WithAttributes":
+				event.Attributes = mergeAttributes(event.Attributes, extractAttributes(innerCall.Args, opt.pkg, resolver))
+			case "WithStackTrace":
+				event.Attributes = mergeAttributes(event.Attributes, []Attribute{{Name: "exception.stacktrace", Type: AttributeTypeString}})
 			}
 		}
 	}
 
-	for _, span := range spanMap {
-		attrMap := make(map[string]bool)
-		for _, attr := range span.Attributes {
-			attrMap[attr.Name] = true
+	for _, span := range targets() {
+		span.Events = mergeEvents(span.Events, event)
+	}
+}
+
+func mergeEvents(existing []Event, event Event) []Event {
+	for i := range existing {
+		if existing[i].Name == event.Name {
+			existing[i].Attributes = mergeAttributes(existing[i].Attributes, event.Attributes)
+			return existing
+		}
+	}
+	return append(existing, event)
+}
+
+// statusCodes maps go.opentelemetry.io/otel/codes values to their names.
+var statusCodes = map[int64]StatusCode{
+	0: StatusCodeUnset,
+	1: StatusCodeError,
+	2: StatusCodeOk,
+}
+
+// extractSpanStatus records the status codes span.SetStatus can set, following
+// helpers such as semconv Status(code) (codes.Code, string), together with the
+// condition guarding each code.
+func extractSpanStatus(callExpr *ast.CallExpr, pkg *packages.Package, resolver *attrResolver, targets func() []*Span) {
+	if len(callExpr.Args) == 0 {
+		return
+	}
+
+	var codeLeaves, descLeaves []exprLeaf
+	if len(callExpr.Args) == 1 {
+		codeLeaves = resolver.expandResult(callExpr.Args[0], pkg, 0)
+		descLeaves = resolver.expandResult(callExpr.Args[0], pkg, 1)
+	} else {
+		codeLeaves = resolver.expand(callExpr.Args[0], pkg)
+		descLeaves = resolver.expand(callExpr.Args[1], pkg)
+	}
+
+	description := false
+	for _, leaf := range descLeaves {
+		description = description || hasDescription(leaf.expr, leaf.pkg)
+	}
+
+	var statuses []SpanStatus
+	for _, leaf := range codeLeaves {
+		code, ok := statusCode(leaf.expr, leaf.pkg)
+		if !ok {
+			continue
 		}
 
-		for _, attr := range attributes {
-			if !attrMap[attr.Name] {
-				span.Attributes = append(span.Attributes, attr)
-				attrMap[attr.Name] = true
+		status := SpanStatus{
+			Code:      code,
+			Condition: enclosingCondition(leaf.expr, leaf.pkg),
+		}
+		if code == StatusCodeError {
+			// Helpers returning (codes.Code, string) pair each code with the
+			// description from the same return statement.
+			if desc, ok := returnedDescription(leaf.expr, leaf.pkg); ok {
+				status.Description = hasDescription(desc, leaf.pkg)
+			} else {
+				status.Description = description
 			}
 		}
+		statuses = append(statuses, status)
 	}
-}
 
-func extractSpanAddEvent(callExpr *ast.CallExpr, spanMap map[SpanKind]*Span, detectedKinds map[SpanKind]bool) {
-	if len(callExpr.Args) < 2 {
+	if len(statuses) == 0 {
 		return
 	}
 
-	for i := 1; i < len(callExpr.Args); i++ {
-		if innerCall, ok := callExpr.Args[i].(*ast.CallExpr); ok {
-			if selExpr, ok := innerCall.Fun.(*ast.SelectorExpr); ok && selExpr.Sel.Name == "WithAttributes" {
-				extractSpanSetAttributes(innerCall, spanMap, detectedKinds)
+	for _, span := range targets() {
+		span.Status = mergeStatuses(span.Status, statuses)
+	}
+}
+
+func hasDescription(expr ast.Expr, pkg *packages.Package) bool {
+	desc, ok := constantString(expr, pkg)
+	return !ok || desc != ""
+}
+
+// returnedDescription returns the description returned alongside a status
+// code in a `return code, description` statement.
+func returnedDescription(expr ast.Expr, pkg *packages.Package) (ast.Expr, bool) {
+	for _, file := range pkg.Syntax {
+		if expr.Pos() < file.FileStart || expr.Pos() > file.FileEnd {
+			continue
+		}
+
+		path, _ := astutil.PathEnclosingInterval(file, expr.Pos(), expr.End())
+		if len(path) > 1 {
+			if ret, ok := path[1].(*ast.ReturnStmt); ok && len(ret.Results) == 2 && ret.Results[0] == expr {
+				return ret.Results[1], true
 			}
 		}
 	}
+
+	return nil, false
 }
 
-func extractMetrics(pkg *packages.Package) []Metric {
-	metricMap := make(map[string]*Metric)
+func statusCode(expr ast.Expr, pkg *packages.Package) (StatusCode, bool) {
+	if pkg.TypesInfo == nil {
+		return "", false
+	}
 
-	// First, look for explicitly created metrics in the code
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || !isNamedType(tv.Type, codesPkgPath, "Code") {
+		return "", false
+	}
+
+	value, ok := constant.Int64Val(tv.Value)
+	if !ok {
+		return "", false
+	}
+
+	code, ok := statusCodes[value]
+	return code, ok
+}
+
+// enclosingCondition renders the innermost if or case condition guarding expr.
+func enclosingCondition(expr ast.Expr, pkg *packages.Package) string {
 	for _, file := range pkg.Syntax {
-		ast.Inspect(file, func(n ast.Node) bool {
-			callExpr, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
+		if expr.Pos() < file.FileStart || expr.Pos() > file.FileEnd {
+			continue
+		}
+
+		path, _ := astutil.PathEnclosingInterval(file, expr.Pos(), expr.End())
+		for i, node := range path {
+			switch n := node.(type) {
+			case *ast.IfStmt:
+				if i > 0 && path[i-1] == n.Else {
+					return "!(" + types.ExprString(n.Cond) + ")"
+				}
+				if i > 0 && path[i-1] == n.Body {
+					return types.ExprString(n.Cond)
+				}
+			case *ast.CaseClause:
+				if n.List == nil {
+					return "default"
+				}
+				// Cases of a tagged switch compare against the tag.
+				var tag ast.Expr
+				if i+2 < len(path) {
+					if stmt, ok := path[i+2].(*ast.SwitchStmt); ok {
+						tag = stmt.Tag
+					}
+				}
+				var cases []string
+				for _, c := range n.List {
+					if tag != nil {
+						cases = append(cases, types.ExprString(tag)+" == "+types.ExprString(c))
+					} else {
+						cases = append(cases, types.ExprString(c))
+					}
+				}
+				return strings.Join(cases, " || ")
+			case *ast.FuncDecl, *ast.FuncLit:
+				return ""
 			}
+		}
+	}
 
-			selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
-			if !ok {
-				return true
+	return ""
+}
+
+func mergeStatuses(existing []SpanStatus, statuses []SpanStatus) []SpanStatus {
+	for _, status := range statuses {
+		found := false
+		for i := range existing {
+			if existing[i].Code == status.Code && existing[i].Condition == status.Condition {
+				existing[i].Description = existing[i].Description || status.Description
+				found = true
+				break
 			}
+		}
+		if !found {
+			existing = append(existing, status)
+		}
+	}
+	return existing
+}
+
+// recordsErrors reports whether a span records errors as exception events.
+func recordsErrors(span Span) bool {
+	for _, event := range span.Events {
+		if event.Name == "exception" {
+			return true
+		}
+	}
+	return false
+}
+
+// spanStatusNote describes the status and error recording behaviour of a span.
+func spanStatusNote(span Span) string {
+	var lines []string
+	for _, status := range span.Status {
+		line := "Sets status `" + string(status.Code) + "`"
+		if status.Condition != "" {
+			line += " when `" + status.Condition + "`"
+		}
+		if status.Description {
+			line += " with a description"
+		}
+		lines = append(lines, line+".")
+	}
+	if recordsErrors(span) {
+		lines = append(lines, "Records errors as `exception` events.")
+	}
+	return strings.Join(lines, "\n")
+}
 
-			methodName := selExpr.Sel.Name
-			metricType := mapMetricType(methodName)
+func extractMetrics(unit libraryUnit, resolver *attrResolver) []Metric {
+	metricMap := make(map[string]*Metric)
+	instrumentBindings := make(map[*ast.CallExpr]ast.Expr)
+	instrumentVars := make(map[types.Object]*Metric)
 
-			if metricType == "" || len(callExpr.Args) == 0 {
+	// First, look for explicitly created metrics in the code
+	unit.inspect(func(n ast.Node, pkg *packages.Package) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 && len(node.Lhs) > 0 {
+				if callExpr, ok := node.Rhs[0].(*ast.CallExpr); ok {
+					instrumentBindings[callExpr] = node.Lhs[0]
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Values) == 1 && len(node.Names) > 0 {
+				if callExpr, ok := node.Values[0].(*ast.CallExpr); ok {
+					instrumentBindings[callExpr] = node.Names[0]
+				}
+			}
+		case *ast.CallExpr:
+			metric := extractInstrument(node, pkg, resolver, metricMap)
+			if metric == nil && unit.reaches(node) {
+				metric = extractConvInstrument(node, pkg, resolver, metricMap)
+			}
+			if metric == nil {
 				return true
 			}
-
-			if lit, ok := callExpr.Args[0].(*ast.BasicLit); ok {
-				metricName := strings.Trim(lit.Value, `"`)
-				if _, exists := metricMap[metricName]; !exists {
-					unit := extractMetricUnit(callExpr)
-					metricMap[metricName] = &Metric{
-						Name: metricName,
-						Type: metricType,
-						Unit: unit,
-					}
+			if binding, ok := instrumentBindings[node]; ok {
+				if obj := exprObject(binding, pkg); obj != nil {
+					instrumentVars[obj] = metric
 				}
 			}
+		}
 
+		return true
+	})
+
+	// Then collect the attributes recorded against each instrument
+	unit.inspect(func(n ast.Node, pkg *packages.Package) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
 			return true
-		})
-	}
+		}
+
+		selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		var instrument ast.Expr
+		switch selExpr.Sel.Name {
+		case "Add", "Record":
+			instrument = selExpr.X
+		case "ObserveInt64", "ObserveFloat64":
+			if len(callExpr.Args) > 0 {
+				instrument = callExpr.Args[0]
+			}
+		default:
+			return true
+		}
+
+		metric, ok := instrumentVars[exprObject(instrument, pkg)]
+		if !ok || len(callExpr.Args) < 3 {
+			return true
+		}
+
+		attrs := extractAttributes(callExpr.Args[2:], pkg, resolver)
+		metric.Attributes = mergeAttributes(metric.Attributes, attrs)
+
+		return true
+	})
 
-	// Add semantic convention metrics based on package type
-	semconvMetrics := getSemConvMetrics(pkg.PkgPath)
-	for _, metric := range semconvMetrics {
-		if _, exists := metricMap[metric.Name]; !exists {
-			metricMap[metric.Name] = &metric
+	// Metrics named as in the registry are references to it.
+	for _, metric := range metricMap {
+		if _, ok := GetSemconvMetric(metric.Name); ok && metric.SemconvRef == "" {
+			metric.SemconvRef = semconvMetricRef(metric.Name)
 		}
 	}
 
@@ -513,157 +1537,275 @@ func extractMetrics(pkg *packages.Package) []Metric {
 	return metrics
 }
 
-func mapMetricType(methodName string) MetricType {
-	lowerName := strings.ToLower(methodName)
-	switch {
-	case strings.Contains(lowerName, string(MetricTypeCounter)) && !strings.Contains(lowerName, "updown"):
-		return MetricTypeCounter
-	case strings.Contains(lowerName, string(MetricTypeHistogram)):
-		return MetricTypeHistogram
-	case strings.Contains(lowerName, string(MetricTypeUpDownCounter)):
-		return MetricTypeUpDownCounter
-	case strings.Contains(lowerName, string(MetricTypeGauge)):
-		return MetricTypeGauge
-	default:
-		return ""
+// meterInstruments maps metric.Meter instrument constructors to the Weaver
+// instrument they produce.
+var meterInstruments = map[string]MetricType{
+	"Int64Counter":                   MetricTypeCounter,
+	"Float64Counter":                 MetricTypeCounter,
+	"Int64UpDownCounter":             MetricTypeUpDownCounter,
+	"Float64UpDownCounter":           MetricTypeUpDownCounter,
+	"Int64Histogram":                 MetricTypeHistogram,
+	"Float64Histogram":               MetricTypeHistogram,
+	"Int64Gauge":                     MetricTypeGauge,
+	"Float64Gauge":                   MetricTypeGauge,
+	"Int64ObservableCounter":         MetricTypeCounter,
+	"Float64ObservableCounter":       MetricTypeCounter,
+	"Int64ObservableUpDownCounter":   MetricTypeUpDownCounter,
+	"Float64ObservableUpDownCounter": MetricTypeUpDownCounter,
+	"Int64ObservableGauge":           MetricTypeGauge,
+	"Float64ObservableGauge":         MetricTypeGauge,
+}
+
+// extractInstrument records the metric created by a metric.Meter instrument
+// constructor call.
+func extractInstrument(callExpr *ast.CallExpr, pkg *packages.Package, resolver *attrResolver, metricMap map[string]*Metric) *Metric {
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	methodName := selExpr.Sel.Name
+	metricType := mapMetricType(methodName)
+
+	if metricType == "" || len(callExpr.Args) == 0 {
+		return nil
+	}
+
+	if !receiverImplements(selExpr, pkg, metricPkgPath, "Meter") {
+		return nil
+	}
+
+	metricName, ok := constantString(callExpr.Args[0], pkg)
+	if !ok {
+		return nil
+	}
+
+	if _, exists := metricMap[metricName]; !exists {
+		metric := &Metric{
+			Name:       metricName,
+			Type:       metricType,
+			Instrument: methodName,
+			Observable: strings.Contains(methodName, "Observable"),
+			When:       resolver.guard(callExpr, pkg),
+		}
+		extractMetricOptions(metric, callExpr, pkg, resolver)
+		metricMap[metricName] = metric
 	}
+
+	return metricMap[metricName]
+}
+
+func mapMetricType(methodName string) MetricType {
+	return meterInstruments[methodName]
 }
 
-func extractMetricUnit(callExpr *ast.CallExpr) string {
+// extractMetricOptions applies the unit, description and explicit bucket
+// boundaries passed to an instrument constructor.
+func extractMetricOptions(metric *Metric, callExpr *ast.CallExpr, pkg *packages.Package, resolver *attrResolver) {
 	if len(callExpr.Args) < 2 {
-		return ""
+		return
 	}
 
 	for i := 1; i < len(callExpr.Args); i++ {
-		optCall, ok := callExpr.Args[i].(*ast.CallExpr)
-		if !ok {
-			continue
+		for _, opt := range resolver.expand(callExpr.Args[i], pkg) {
+			optCall, ok := opt.expr.(*ast.CallExpr)
+			if !ok || len(optCall.Args) == 0 {
+				continue
+			}
+
+			selExpr, ok := optCall.Fun.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+
+			switch selExpr.Sel.Name {
+			case "WithUnit":
+				if unit, ok := constantString(optCall.Args[0], opt.pkg); ok {
+					metric.Unit = unit
+				}
+			case "WithDescription":
+				if description, ok := constantString(optCall.Args[0], opt.pkg); ok {
+					metric.Description = description
+				}
+			case "WithExplicitBucketBoundaries":
+				metric.BucketBoundaries = extractBucketBoundaries(optCall.Args, opt.pkg, resolver)
+			case "WithInt64Callback", "WithFloat64Callback":
+				extractCallbackAttributes(metric, optCall.Args[0], opt.pkg, resolver)
+			}
 		}
+	}
+}
 
-		selExpr, ok := optCall.Fun.(*ast.SelectorExpr)
-		if !ok {
+// extractCallbackAttributes collects the attributes observed by an
+// asynchronous instrument callback, e.g. o.Observe(v, metric.WithAttributes(...)).
+func extractCallbackAttributes(metric *Metric, callback ast.Expr, pkg *packages.Package, resolver *attrResolver) {
+	for _, leaf := range resolver.expand(callback, pkg) {
+		body, bodyPkg := resolver.funcBody(leaf.expr, leaf.pkg)
+		if body == nil {
 			continue
 		}
 
-		if selExpr.Sel.Name == "WithUnit" && len(optCall.Args) > 0 {
-			if lit, ok := optCall.Args[0].(*ast.BasicLit); ok {
-				return strings.Trim(lit.Value, `"`)
+		ast.Inspect(body, func(n ast.Node) bool {
+			callExpr, ok := n.(*ast.CallExpr)
+			if !ok || len(callExpr.Args) < 2 {
+				return true
 			}
-		}
-	}
 
-	return ""
+			selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+			if !ok || selExpr.Sel.Name != "Observe" {
+				return true
+			}
+
+			if !receiverImplements(selExpr, bodyPkg, metricPkgPath, "Int64Observer") &&
+				!receiverImplements(selExpr, bodyPkg, metricPkgPath, "Float64Observer") {
+				return true
+			}
+
+			attrs := extractAttributes(callExpr.Args[1:], bodyPkg, resolver)
+			metric.Attributes = mergeAttributes(metric.Attributes, attrs)
+
+			return true
+		})
+	}
 }
 
-func getSemConvAttributesForSpan(spanKind SpanKind, pkgPath string) []Attribute {
-	pkgLower := strings.ToLower(pkgPath)
+func extractBucketBoundaries(args []ast.Expr, pkg *packages.Package, resolver *attrResolver) []float64 {
+	var boundaries []float64
 
-	if spanKind == SpanKindServer && isHTTPPackage(pkgLower) {
-		return []Attribute{
-			{Name: "http.request.method", Type: AttributeTypeString},
-			{Name: "http.response.status_code", Type: AttributeTypeLong},
-			{Name: "http.route", Type: AttributeTypeString},
-			{Name: "server.address", Type: AttributeTypeString},
-			{Name: "server.port", Type: AttributeTypeLong},
-			{Name: "url.scheme", Type: AttributeTypeString},
-			{Name: "url.path", Type: AttributeTypeString},
-			{Name: "network.protocol.name", Type: AttributeTypeString},
-			{Name: "network.protocol.version", Type: AttributeTypeString},
-			{Name: "user_agent.original", Type: AttributeTypeString},
-			{Name: "client.address", Type: AttributeTypeString},
-			{Name: "network.peer.address", Type: AttributeTypeString},
+	for _, arg := range args {
+		for _, leaf := range resolver.expand(arg, pkg) {
+			if value, ok := constantFloat(leaf.expr, leaf.pkg); ok {
+				boundaries = append(boundaries, value)
+			}
 		}
 	}
 
-	if spanKind == SpanKindClient && isHTTPPackage(pkgLower) {
-		return []Attribute{
-			{Name: "http.request.method", Type: AttributeTypeString},
-			{Name: "http.response.status_code", Type: AttributeTypeLong},
-			{Name: "server.address", Type: AttributeTypeString},
-			{Name: "server.port", Type: AttributeTypeLong},
-			{Name: "url.full", Type: AttributeTypeString},
-			{Name: "network.protocol.name", Type: AttributeTypeString},
-			{Name: "network.protocol.version", Type: AttributeTypeString},
-		}
+	return boundaries
+}
+
+func constantFloat(expr ast.Expr, pkg *packages.Package) (float64, bool) {
+	if pkg.TypesInfo == nil {
+		return 0, false
 	}
 
-	if spanKind == SpanKindClient && isDatabasePackage(pkgLower) {
-		return []Attribute{
-			{Name: "db.system", Type: AttributeTypeString},
-			{Name: "db.operation.name", Type: AttributeTypeString},
-			{Name: "db.collection.name", Type: AttributeTypeString},
-			{Name: "db.query.text", Type: AttributeTypeString},
-			{Name: "server.address", Type: AttributeTypeString},
-			{Name: "server.port", Type: AttributeTypeLong},
-		}
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return 0, false
 	}
 
-	if (spanKind == SpanKindServer || spanKind == SpanKindClient) && isRPCPackage(pkgLower) {
-		return []Attribute{
-			{Name: "rpc.system", Type: AttributeTypeString},
-			{Name: "rpc.service", Type: AttributeTypeString},
-			{Name: "rpc.method", Type: AttributeTypeString},
-			{Name: "server.address", Type: AttributeTypeString},
-			{Name: "server.port", Type: AttributeTypeLong},
-		}
+	value, ok := constant.Float64Val(constant.ToFloat(tv.Value))
+	return value, ok
+}
+
+// semconvSpanAttributes lists the attributes the registry span groups of
+// kind define for the namespaces of attrs, e.g. span.http.server for a server
+// span recording http.request.method. Spans recording no attributes at all
+// fall back to the semconv namespaces the unit references.
+func semconvSpanAttributes(kind SpanKind, attrs []Attribute, unitNamespaces []string) []Attribute {
+	var namespaces []string
+	for _, attr := range attrs {
+		namespaces = mergeStrings(namespaces, []string{attributeNamespace(attr.Name)})
 	}
 
-	if spanKind == SpanKindServer && isLambdaPackage(pkgLower) {
-		return []Attribute{
-			{Name: "faas.invocation_id", Type: AttributeTypeString},
-			{Name: "cloud.resource_id", Type: AttributeTypeString},
-		}
+	groups := semconvSpansIn(kind, namespaces)
+	if len(attrs) == 0 {
+		groups = semconvSpansIn(kind, unitNamespaces)
 	}
 
-	if spanKind == SpanKindClient && isAWSPackage(pkgLower) {
-		return []Attribute{
-			{Name: "rpc.system", Type: AttributeTypeString},
-			{Name: "rpc.service", Type: AttributeTypeString},
-			{Name: "rpc.method", Type: AttributeTypeString},
-			{Name: "server.address", Type: AttributeTypeString},
-			{Name: "server.port", Type: AttributeTypeLong},
+	var semconvAttrs []Attribute
+	for _, group := range groups {
+		for _, ref := range group.Attributes {
+			attr := Attribute{
+				Name:             ref.Ref,
+				Type:             AttributeTypeString,
+				RequirementLevel: ref.RequirementLevel,
+				Condition:        ref.Condition,
+			}
+			// Weaver defaults refs without a requirement level to recommended.
+			if attr.RequirementLevel == "" {
+				attr.RequirementLevel = RequirementLevelRecommended
+			}
+			if semconvAttr, ok := GetSemconvAttribute(ref.Ref); ok {
+				attr.Type = AttributeType(semconvAttr.Type)
+				attr.Examples = semconvAttr.Examples
+			}
+			semconvAttrs = appendMissingAttributes(semconvAttrs, attr)
 		}
 	}
-
-	return nil
-}
-
-func isAWSPackage(pkgPath string) bool {
-	return strings.Contains(pkgPath, "aws")
+	return semconvAttrs
 }
 
-func isHTTPPackage(pkgPath string) bool {
-	return strings.Contains(pkgPath, "http") ||
-		strings.Contains(pkgPath, "gin") ||
-		strings.Contains(pkgPath, "echo") ||
-		strings.Contains(pkgPath, "mux") ||
-		strings.Contains(pkgPath, "restful")
+func semconvSpansIn(kind SpanKind, namespaces []string) []SemconvSpan {
+	var groups []SemconvSpan
+	for _, namespace := range namespaces {
+		groups = append(groups, GetSemconvSpans(kind, namespace)...)
+	}
+	return groups
 }
 
-func isDatabasePackage(pkgPath string) bool {
-	return strings.Contains(pkgPath, "mongo") ||
-		strings.Contains(pkgPath, "database") ||
-		strings.Contains(pkgPath, "sql")
+// appendMissingAttributes appends attributes not already present by name,
+// leaving those present untouched.
+func appendMissingAttributes(existing []Attribute, attributes ...Attribute) []Attribute {
+	for _, attr := range attributes {
+		found := false
+		for _, e := range existing {
+			if e.Name == attr.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, attr)
+		}
+	}
+	return existing
 }
 
-func isRPCPackage(pkgPath string) bool {
-	return strings.Contains(pkgPath, "grpc")
+func attributeNamespace(name string) string {
+	if i := strings.Index(name, "."); i != -1 {
+		return name[:i]
+	}
+	return name
 }
 
-func isLambdaPackage(pkgPath string) bool {
-	return strings.Contains(pkgPath, "lambda")
-}
+// semconvNamespaces lists the attribute namespaces of the semconv attribute
+// keys and instrument helper packages (httpconv, rpcconv, ...) the unit
+// references.
+func semconvNamespaces(unit libraryUnit) []string {
+	var namespaces []string
+	unit.inspect(func(n ast.Node, pkg *packages.Package) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || pkg.TypesInfo == nil {
+			return true
+		}
+		obj := pkg.TypesInfo.Uses[ident]
+		if obj == nil || obj.Pkg() == nil || semconvVersion(obj.Pkg().Path()) == "" {
+			return true
+		}
 
-func isRuntimePackage(pkgPath string) bool {
-	return strings.HasSuffix(pkgPath, "/instrumentation/runtime")
+		if c, ok := obj.(*types.Const); ok && c.Val().Kind() == constant.String {
+			namespaces = mergeStrings(namespaces, []string{attributeNamespace(constant.StringVal(c.Val()))})
+		} else if name := obj.Pkg().Name(); strings.HasSuffix(name, "conv") {
+			namespaces = mergeStrings(namespaces, []string{strings.TrimSuffix(name, "conv")})
+		}
+		return true
+	})
+	sort.Strings(namespaces)
+	return namespaces
 }
 
-func isHostPackage(pkgPath string) bool {
-	return strings.HasSuffix(pkgPath, "/instrumentation/host")
+func makeSpanGroupID(pkgName string, spanName string, kind SpanKind) string {
+	if kind == "" {
+		return pkgName + ".unknown.span"
+	}
+	if spanName == "" {
+		return fmt.Sprintf("%s.%s.span", pkgName, strings.ToLower(string(kind)))
+	}
+	return fmt.Sprintf("%s.%s.%s.span", pkgName, sanitizeSpanName(spanName), strings.ToLower(string(kind)))
 }
 
-func makeSpanGroupID(pkgName string, kind SpanKind) string {
-	return fmt.Sprintf("%s.%s.span", pkgName, strings.ToLower(string(kind)))
+func makeEventGroupID(pkgName, eventName string) string {
+	return fmt.Sprintf("%s.event.%s", pkgName, sanitizeSpanName(eventName))
 }
 
 func makeMetricGroupID(pkgName, metricName string) string {
@@ -677,42 +1819,86 @@ func convertTelemetryToGroups(pkgPath string, telemetry []Telemetry) []Group {
 	for _, tel := range telemetry {
 		for _, span := range tel.Spans {
 			attrs := convertAttributesToRefs(span.Attributes)
-			if len(attrs) == 0 {
+			if len(attrs) == 0 && len(span.Events) == 0 {
 				continue
 			}
 
-			groupID := makeSpanGroupID(pkgName, span.Kind)
-			if existing, ok := groupMap[groupID]; ok {
-				attrMap := make(map[string]bool)
-				for _, attr := range existing.Attributes {
-					attrMap[attr.Ref] = true
+			var eventNames []string
+			for _, event := range span.Events {
+				eventNames = append(eventNames, event.Name)
+
+				// Registry events are referenced by name, not redefined.
+				if event.SemconvRef != "" {
+					continue
 				}
-				for _, attr := range attrs {
-					if !attrMap[attr.Ref] {
-						existing.Attributes = append(existing.Attributes, attr)
-					}
+
+				eventID := makeEventGroupID(pkgName, event.Name)
+				if existing, ok := groupMap[eventID]; ok {
+					existing.Attributes = mergeAttributeRefs(existing.Attributes, convertAttributesToRefs(event.Attributes))
+					continue
+				}
+				groupMap[eventID] = &Group{
+					ID:         eventID,
+					Type:       "event",
+					EventName:  event.Name,
+					Stability:  StabilityDevelopment,
+					Brief:      "Event " + event.Name + " for " + pkgName,
+					Attributes: convertAttributesToRefs(event.Attributes),
+					Annotations: map[string]interface{}{
+						"source": event.Source,
+					},
+				}
+			}
+
+			groupID := makeSpanGroupID(pkgName, span.Name, span.Kind)
+			if existing, ok := groupMap[groupID]; ok {
+				existing.Attributes = mergeAttributeRefs(existing.Attributes, attrs)
+				existing.Events = mergeStrings(existing.Events, eventNames)
+				if existing.Note == "" {
+					existing.Note = spanStatusNote(span)
 				}
 			} else {
-				groupMap[groupID] = &Group{
+				group := &Group{
 					ID:         groupID,
 					Type:       "span",
 					Name:       pkgName + " " + strings.ToLower(string(span.Kind)) + " span",
 					Stability:  StabilityDevelopment,
 					Brief:      "Span for " + pkgName,
+					Note:       spanStatusNote(span),
 					SpanKind:   span.Kind,
+					Events:     eventNames,
 					Attributes: attrs,
 				}
+				if span.Kind == "" {
+					group.Name = pkgName + " unknown span"
+					group.Brief = "Spans started outside " + pkgName + ", e.g. taken from the context"
+				}
+				if span.Name != "" {
+					group.Name = pkgName + " " + span.Name + " " + strings.ToLower(string(span.Kind)) + " span"
+					group.Brief = "Span " + span.Name + " for " + pkgName
+					group.Annotations = map[string]interface{}{
+						"span_name": span.Name,
+						"source":    span.Source,
+					}
+				}
+				if span.When != "" {
+					if group.Annotations == nil {
+						group.Annotations = make(map[string]interface{})
+					}
+					group.Annotations["when"] = span.When
+				}
+				groupMap[groupID] = group
 			}
 		}
 
 		for _, metric := range tel.Metrics {
-			if _, ok := GetSemconvMetric(metric.Name); ok {
+			if metric.SemconvRef != "" {
 				continue
 			}
 
 			groupID := makeMetricGroupID(pkgName, metric.Name)
 			if _, ok := groupMap[groupID]; !ok {
-				groupMap[groupID] = &Group{
+				group := &Group{
 					ID:         groupID,
 					Type:       "metric",
 					MetricName: metric.Name,
@@ -722,6 +1908,22 @@ func convertTelemetryToGroups(pkgPath string, telemetry []Telemetry) []Group {
 					Brief:      "Metric " + metric.Name,
 					Attributes: convertAttributesToRefs(metric.Attributes),
 				}
+				if metric.Description != "" {
+					group.Brief = metric.Description
+				}
+				if len(metric.BucketBoundaries) > 0 || metric.Observable || metric.When != "" {
+					group.Annotations = make(map[string]interface{})
+				}
+				if metric.When != "" {
+					group.Annotations["when"] = metric.When
+				}
+				if len(metric.BucketBoundaries) > 0 {
+					group.Annotations["explicit_bucket_boundaries"] = metric.BucketBoundaries
+				}
+				if metric.Observable {
+					group.Annotations["observable"] = true
+				}
+				groupMap[groupID] = group
 			}
 		}
 	}
@@ -734,195 +1936,109 @@ func convertTelemetryToGroups(pkgPath string, telemetry []Telemetry) []Group {
 	return groups
 }
 
+func mergeAttributeRefs(existing []AttributeRef, attrs []AttributeRef) []AttributeRef {
+	attrMap := make(map[string]bool)
+	for _, attr := range existing {
+		attrMap[attr.Ref] = true
+	}
+	for _, attr := range attrs {
+		if !attrMap[attr.Ref] {
+			existing = append(existing, attr)
+			attrMap[attr.Ref] = true
+		}
+	}
+	return existing
+}
+
+// mergeStrings appends values not already present.
+func mergeStrings(existing []string, values []string) []string {
+	for _, value := range values {
+		found := false
+		for _, e := range existing {
+			if e == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, value)
+		}
+	}
+	return existing
+}
+
 func convertAttributesToRefs(attrs []Attribute) []AttributeRef {
 	var refs []AttributeRef
 	for _, attr := range attrs {
-		refs = append(refs, AttributeRef{
+		ref := AttributeRef{
 			Ref:              attr.Name,
-			RequirementLevel: "recommended",
-		})
+			RequirementLevel: RequirementLevelRecommended,
+			Type:             attr.Type,
+			Examples:         attr.Examples,
+			Enum:             attr.Enum,
+		}
+		// Attributes taken from a semantic convention keep its level. Those
+		// gated behind an option are opt-in, those gated behind an
+		// environment variable or set inside other branches are
+		// conditionally required and those passed to Tracer.Start
+		// unconditionally are required.
+		switch {
+		case attr.RequirementLevel != "":
+			ref.RequirementLevel = attr.RequirementLevel
+			ref.Condition = attr.Condition
+		case isOptionGuard(attr.When):
+			ref.RequirementLevel = RequirementLevelOptIn
+			ref.Note = "Only recorded when `" + attr.When + "`."
+		case attr.When != "":
+			ref.RequirementLevel = RequirementLevelConditionallyRequired
+			ref.Condition = attr.When
+		case attr.Condition != "":
+			ref.RequirementLevel = RequirementLevelConditionallyRequired
+			ref.Condition = attr.Condition
+		case attr.Start:
+			ref.RequirementLevel = RequirementLevelRequired
+		}
+		refs = append(refs, ref)
 	}
 	return refs
 }
 
+// isOptionGuard reports whether a guard label names a With* option rather
+// than an environment variable setting, which is labelled NAME=value.
+func isOptionGuard(when string) bool {
+	return when != "" && !strings.Contains(when, "=")
+}
+
 func sanitizePackageName(pkgPath string) string {
 	parts := strings.Split(pkgPath, "/")
 	name := parts[len(parts)-1]
 	return strings.TrimPrefix(name, "otel")
 }
 
-func sanitizeMetricName(metricName string) string {
-	return strings.ReplaceAll(metricName, ".", "_")
-}
-
-func getSemConvMetrics(pkgPath string) []Metric {
-	pkgLower := strings.ToLower(pkgPath)
-
-	if isHTTPPackage(pkgLower) {
-		return []Metric{
-			{
-				Name: "http.server.request.duration",
-				Type: MetricTypeHistogram,
-				Unit: "s",
-				Attributes: []Attribute{
-					{Name: "http.request.method", Type: AttributeTypeString},
-					{Name: "http.response.status_code", Type: AttributeTypeLong},
-					{Name: "http.route", Type: AttributeTypeString},
-					{Name: "network.protocol.version", Type: AttributeTypeString},
-					{Name: "url.scheme", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "http.server.request.body.size",
-				Type: MetricTypeHistogram,
-				Unit: "By",
-				Attributes: []Attribute{
-					{Name: "http.request.method", Type: AttributeTypeString},
-					{Name: "http.response.status_code", Type: AttributeTypeLong},
-					{Name: "http.route", Type: AttributeTypeString},
-					{Name: "network.protocol.version", Type: AttributeTypeString},
-					{Name: "url.scheme", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "http.server.response.body.size",
-				Type: MetricTypeHistogram,
-				Unit: "By",
-				Attributes: []Attribute{
-					{Name: "http.request.method", Type: AttributeTypeString},
-					{Name: "http.response.status_code", Type: AttributeTypeLong},
-					{Name: "http.route", Type: AttributeTypeString},
-					{Name: "network.protocol.version", Type: AttributeTypeString},
-					{Name: "url.scheme", Type: AttributeTypeString},
-				},
-			},
-		}
-	}
-
-	if isRPCPackage(pkgLower) {
-		return []Metric{
-			{
-				Name: "rpc.server.duration",
-				Type: MetricTypeHistogram,
-				Unit: "ms",
-				Attributes: []Attribute{
-					{Name: "rpc.method", Type: AttributeTypeString},
-					{Name: "rpc.service", Type: AttributeTypeString},
-					{Name: "rpc.system", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "rpc.server.request.size",
-				Type: MetricTypeHistogram,
-				Unit: "By",
-				Attributes: []Attribute{
-					{Name: "rpc.method", Type: AttributeTypeString},
-					{Name: "rpc.service", Type: AttributeTypeString},
-					{Name: "rpc.system", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "rpc.server.response.size",
-				Type: MetricTypeHistogram,
-				Unit: "By",
-				Attributes: []Attribute{
-					{Name: "rpc.method", Type: AttributeTypeString},
-					{Name: "rpc.service", Type: AttributeTypeString},
-					{Name: "rpc.system", Type: AttributeTypeString},
-				},
-			},
-		}
-	}
-
-	if isRuntimePackage(pkgPath) {
-		return []Metric{
-			{
-				Name: "go.memory.used",
-				Type: MetricTypeGauge,
-				Unit: "By",
-			},
-			{
-				Name: "go.memory.limit",
-				Type: MetricTypeGauge,
-				Unit: "By",
-			},
-			{
-				Name: "go.memory.allocated",
-				Type: MetricTypeCounter,
-				Unit: "By",
-			},
-			{
-				Name: "go.memory.allocations",
-				Type: MetricTypeCounter,
-				Unit: "{allocation}",
-			},
-			{
-				Name: "go.memory.gc.goal",
-				Type: MetricTypeGauge,
-				Unit: "By",
-			},
-			{
-				Name: "go.goroutine.count",
-				Type: MetricTypeGauge,
-				Unit: "{goroutine}",
-			},
-			{
-				Name: "go.processor.limit",
-				Type: MetricTypeGauge,
-				Unit: "{thread}",
-			},
-			{
-				Name: "go.config.gogc",
-				Type: MetricTypeGauge,
-				Unit: "%",
-			},
-		}
-	}
-
-	if isHostPackage(pkgPath) {
-		return []Metric{
-			{
-				Name: "process.cpu.time",
-				Type: MetricTypeCounter,
-				Unit: "s",
-				Attributes: []Attribute{
-					{Name: "state", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "system.cpu.time",
-				Type: MetricTypeCounter,
-				Unit: "s",
-				Attributes: []Attribute{
-					{Name: "state", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "system.memory.usage",
-				Type: MetricTypeGauge,
-				Unit: "By",
-				Attributes: []Attribute{
-					{Name: "state", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "system.memory.utilization",
-				Type: MetricTypeGauge,
-				Unit: "1",
-				Attributes: []Attribute{
-					{Name: "state", Type: AttributeTypeString},
-				},
-			},
-			{
-				Name: "system.network.io",
-				Type: MetricTypeCounter,
-				Unit: "By",
-				Attributes: []Attribute{
-					{Name: "direction", Type: AttributeTypeString},
-				},
-			},
+// sanitizeSpanName reduces a span name or template to an ID segment.
+func sanitizeSpanName(spanName string) string {
+	var b strings.Builder
+	lastSep := true
+	for _, r := range strings.ToLower(spanName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastSep = false
+		case r == '.':
+			if !lastSep {
+				b.WriteRune('.')
+				lastSep = true
+			}
+		default:
+			if !lastSep {
+				b.WriteRune('_')
+				lastSep = true
+			}
 		}
 	}
+	return strings.Trim(b.String(), "._")
+}
 
-	return nil
+func sanitizeMetricName(metricName string) string {
+	return strings.ReplaceAll(metricName, ".", "_")
 }
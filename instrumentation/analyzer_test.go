@@ -3,6 +3,9 @@ package instrumentation
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -141,20 +144,8 @@ require (
 			t.Fatalf("AnalyzePackage() error = %v", err)
 		}
 
-		if got := len(analysis.SemanticConventions); got == 0 {
-			t.Error("SemanticConventions is empty, expected at least one")
-		}
-
-		foundSemconv := false
-		for _, conv := range analysis.SemanticConventions {
-			if conv == "go.opentelemetry.io/otel/semconv/v1.20.0" {
-				foundSemconv = true
-				break
-			}
-		}
-
-		if !foundSemconv {
-			t.Errorf("SemanticConventions = %v, want to contain semconv import", analysis.SemanticConventions)
+		if want := []string{"v1.20.0"}; !reflect.DeepEqual(analysis.SemconvVersions, want) {
+			t.Errorf("SemconvVersions = %v, want %v", analysis.SemconvVersions, want)
 		}
 	})
 
@@ -342,8 +333,8 @@ require go.opentelemetry.io/otel v1.38.0
 	})
 }
 
-func TestExtractSemanticConventions(t *testing.T) {
-	t.Run("extractSemanticConventions - finds semconv imports", func(t *testing.T) {
+func TestExtractSemconvVersions(t *testing.T) {
+	t.Run("extractSemconvVersions - finds semconv imports", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
 		content := `package testpkg
@@ -378,13 +369,12 @@ require go.opentelemetry.io/otel v1.38.0
 			t.Fatalf("AnalyzePackage() error = %v", err)
 		}
 
-		conventions := analysis.SemanticConventions
-		if got := len(conventions); got == 0 {
-			t.Error("extractSemanticConventions() found no conventions, want at least 1")
+		if want := []string{"v1.20.0"}; !reflect.DeepEqual(analysis.SemconvVersions, want) {
+			t.Errorf("SemconvVersions = %v, want %v", analysis.SemconvVersions, want)
 		}
 	})
 
-	t.Run("extractSemanticConventions - ignores non-semconv imports", func(t *testing.T) {
+	t.Run("extractSemconvVersions - ignores non-semconv imports", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
 		content := `package testpkg
@@ -418,9 +408,74 @@ go 1.24
 			t.Fatalf("AnalyzePackage() error = %v", err)
 		}
 
-		conventions := analysis.SemanticConventions
-		if got := len(conventions); got != 0 {
-			t.Errorf("extractSemanticConventions() found %d conventions, want 0", got)
+		if got := len(analysis.SemconvVersions); got != 0 {
+			t.Errorf("SemconvVersions = %v, want none", analysis.SemconvVersions)
+		}
+	})
+
+	t.Run("extractSemconvVersions - records versions imported by the package and internal helpers", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"example.com/testpkg/internal/semconv"
+	semconvnew "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+var keys = []string{string(semconvnew.HTTPRequestMethodKey), semconv.Method}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		helper := `package semconv
+
+import semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+
+var Method = string(semconv.HTTPMethodKey)
+`
+		helperDir := filepath.Join(tmpDir, "internal", "semconv")
+		if err := os.MkdirAll(helperDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(helperDir, "semconv.go"), []byte(helper), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		unused := `package example
+
+import semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+var Method = string(semconv.HTTPMethodKey)
+`
+		exampleDir := filepath.Join(tmpDir, "example")
+		if err := os.MkdirAll(exampleDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(exampleDir, "main.go"), []byte(unused), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := []string{"v1.20.0", "v1.37.0"}
+		if !reflect.DeepEqual(analysis.SemconvVersions, want) {
+			t.Errorf("SemconvVersions = %v, want %v", analysis.SemconvVersions, want)
 		}
 	})
 }
@@ -510,8 +565,8 @@ require go.opentelemetry.io/otel v1.38.0
 	})
 }
 
-func TestExtractSpanAddEvent(t *testing.T) {
-	t.Run("extractSpans - captures AddEvent with attributes", func(t *testing.T) {
+func TestResolveAttributeKeys(t *testing.T) {
+	t.Run("extractSpans - resolves constant, attribute.Key and semconv keys", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
 		content := `package testpkg
@@ -519,17 +574,29 @@ func TestExtractSpanAddEvent(t *testing.T) {
 import (
 	"context"
 	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
-func instrument(ctx context.Context, tracer trace.Tracer) {
-	ctx, span := tracer.Start(ctx, "operation", trace.WithSpanKind(trace.SpanKindClient))
+const (
+	operationKey = "custom.operation"
+	retriesKey   = attribute.Key("custom.retries")
+)
+
+func instrument(ctx context.Context, tracer trace.Tracer, method, route string) {
+	ctx, span := tracer.Start(ctx, "operation", trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	span.AddEvent("error.occurred", trace.WithAttributes(
-		attribute.String("error.type", "timeout"),
-		attribute.String("error.message", "connection timeout"),
-	))
+	span.SetAttributes(
+		attribute.String(operationKey, "find"),
+		attribute.Key("custom.cached").Bool(true),
+		attribute.Key("custom.tags").StringSlice([]string{"a"}),
+		retriesKey.Int(3),
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.HTTPRoute(route),
+		semconv.HTTPResponseStatusCode(200),
+		semconv.HTTPRequestMethodGet,
+	)
 }
 `
 		filePath := filepath.Join(tmpDir, "test.go")
@@ -562,44 +629,70 @@ require go.opentelemetry.io/otel v1.38.0
 			t.Fatalf("Spans count = %d, want 1", got)
 		}
 
-		span := tel.Spans[0]
-		foundErrorType := false
-		foundErrorMsg := false
-		for _, attr := range span.Attributes {
-			if attr.Name == "error.type" && attr.Type == AttributeTypeString {
-				foundErrorType = true
-			}
-			if attr.Name == "error.message" && attr.Type == AttributeTypeString {
-				foundErrorMsg = true
-			}
+		want := map[string]AttributeType{
+			"custom.operation":          AttributeTypeString,
+			"custom.cached":             AttributeTypeBoolean,
+			"custom.tags":               AttributeTypeStringArray,
+			"custom.retries":            AttributeTypeLong,
+			"http.request.method":       AttributeTypeString,
+			"http.route":                AttributeTypeString,
+			"http.response.status_code": AttributeTypeLong,
 		}
 
-		if !foundErrorType {
-			t.Error("Expected error.type STRING attribute from AddEvent not found")
+		got := make(map[string]AttributeType)
+		for _, attr := range tel.Spans[0].Attributes {
+			got[attr.Name] = attr.Type
 		}
-		if !foundErrorMsg {
-			t.Error("Expected error.message STRING attribute from AddEvent not found")
+
+		for name, attrType := range want {
+			if got[name] != attrType {
+				t.Errorf("Attribute %s type = %q, want %q", name, got[name], attrType)
+			}
 		}
 	})
 }
 
-func TestExtractMetricUnit(t *testing.T) {
-	t.Run("extractMetrics - captures metric unit from WithUnit option", func(t *testing.T) {
+func TestExtractAttributeExamples(t *testing.T) {
+	t.Run("extractSpans - captures literal attribute values as examples", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
 		content := `package testpkg
 
 import (
-	"go.opentelemetry.io/otel/metric"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func instrument(meter metric.Meter) {
-	counter, _ := meter.Int64Counter("requests.total", metric.WithUnit("1"))
-	histogram, _ := meter.Float64Histogram("request.duration", metric.WithUnit("ms"))
+const system = "kafka"
+
+func publish(ctx context.Context, tracer trace.Tracer, topic string) {
+	_, span := tracer.Start(ctx, "publish",
+		trace.WithAttributes(
+			attribute.String("messaging.system", system),
+			attribute.Int("rpc.grpc.status_code", 0),
+			attribute.StringSlice("custom.tags", []string{"a", "b"}),
+			attribute.String("messaging.destination.name", topic),
+			attribute.Bool("custom.retry", retry(ctx)),
+		),
+	)
+	defer span.End()
+}
+
+func retry(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
+
+func orders(ctx context.Context, tracer trace.Tracer) {
+	publish(ctx, tracer, "orders")
+}
+
+func payments(ctx context.Context, tracer trace.Tracer) {
+	publish(ctx, tracer, "payments")
 }
 `
-		filePath := filepath.Join(tmpDir, "test.go")
-		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
 			t.Fatal(err)
 		}
 
@@ -619,105 +712,1831 @@ require go.opentelemetry.io/otel v1.38.0
 			t.Fatalf("AnalyzePackage() error = %v", err)
 		}
 
-		if got := len(analysis.Telemetry); got != 1 {
-			t.Fatalf("Telemetry count = %d, want 1", got)
+		span := findSpan(t, analysis.Telemetry[0].Spans, "publish")
+
+		want := map[string][]string{
+			"messaging.system":           {"kafka"},
+			"rpc.grpc.status_code":       {"0"},
+			"custom.tags":                {"a", "b"},
+			"messaging.destination.name": {"orders", "payments"},
+			"custom.retry":               nil,
+		}
+		if got := len(span.Attributes); got != len(want) {
+			t.Fatalf("Span attributes count = %d, want %d", got, len(want))
+		}
+		for _, attr := range span.Attributes {
+			if !reflect.DeepEqual(attr.Examples, want[attr.Name]) {
+				t.Errorf("Attribute %s examples = %v, want %v", attr.Name, attr.Examples, want[attr.Name])
+			}
 		}
+	})
+}
 
-		tel := analysis.Telemetry[0]
-		if got := len(tel.Metrics); got != 2 {
-			t.Fatalf("Metrics count = %d, want 2", got)
+func TestInferEnumAttributes(t *testing.T) {
+	t.Run("extractSpans - marks attributes taking only constant values as enums", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Op int
+
+const (
+	Get Op = iota
+	Put
+)
+
+func operation(op Op) string {
+	var name string
+	switch op {
+	case Get:
+		name = "get"
+	case Put:
+		name = "put"
+	}
+	return name
+}
+
+func do(ctx context.Context, tracer trace.Tracer, op Op, key string) {
+	_, span := tracer.Start(ctx, "do", trace.WithAttributes(
+		attribute.String("cache.operation", operation(op)),
+		attribute.String("cache.key", key),
+	))
+	defer span.End()
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
 		}
 
-		foundCounterUnit := false
-		foundHistogramUnit := false
-		for _, metric := range tel.Metrics {
-			if metric.Name == "requests.total" && metric.Unit == "1" {
-				foundCounterUnit = true
-			}
-			if metric.Name == "request.duration" && metric.Unit == "ms" {
-				foundHistogramUnit = true
-			}
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
 		}
 
-		if !foundCounterUnit {
-			t.Error("Expected requests.total metric with unit '1' not found")
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
 		}
-		if !foundHistogramUnit {
-			t.Error("Expected request.duration metric with unit 'ms' not found")
+
+		span := findSpan(t, analysis.Telemetry[0].Spans, "do")
+		for _, attr := range span.Attributes {
+			switch attr.Name {
+			case "cache.operation":
+				if !attr.Enum || !reflect.DeepEqual(attr.Examples, []string{"get", "put"}) {
+					t.Errorf("cache.operation enum = %v examples = %v, want enum of [get put]", attr.Enum, attr.Examples)
+				}
+			case "cache.key":
+				if attr.Enum {
+					t.Error("cache.key is an enum, want plain string")
+				}
+			}
 		}
 	})
 }
 
-func TestGetSemConvMetrics(t *testing.T) {
-	t.Run("getSemConvMetrics - returns runtime metrics", func(t *testing.T) {
-		metrics := getSemConvMetrics("go.opentelemetry.io/contrib/instrumentation/runtime")
+func TestInferRequirementLevels(t *testing.T) {
+	t.Run("convertTelemetryToGroups - infers requirement levels from control flow", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type config struct {
+	Peer bool
+}
+
+// Option specifies instrumentation configuration options.
+type Option func(*config)
+
+// WithPeer records the peer name.
+func WithPeer() Option {
+	return func(c *config) {
+		c.Peer = true
+	}
+}
+
+func query(ctx context.Context, tracer trace.Tracer, cfg config, sampled bool) error {
+	if !sampled {
+		return nil
+	}
+
+	_, span := tracer.Start(ctx, "query", trace.WithAttributes(attribute.String("db.system.name", "custom")))
+	defer span.End()
+
+	if cfg.Peer {
+		span.SetAttributes(attribute.String("peer.service", "db"))
+	}
+
+	span.SetAttributes(attribute.Bool("db.cached", false))
+
+	switch system := dbSystem(); system {
+	case "postgresql", "mysql":
+		span.SetAttributes(attribute.String("db.namespace", system))
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		span.SetAttributes(attribute.String("error.type", "canceled"))
+	}
+	return err
+}
 
-		if got := len(metrics); got != 8 {
-			t.Fatalf("Runtime metrics count = %d, want 8", got)
+func dbSystem() string {
+	return "postgresql"
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
 		}
 
-		expectedMetrics := map[string]struct {
-			metric MetricType
-			unit   string
-		}{
-			"go.memory.used":        {MetricTypeGauge, "By"},
-			"go.memory.limit":       {MetricTypeGauge, "By"},
-			"go.memory.allocated":   {MetricTypeCounter, "By"},
-			"go.memory.allocations": {MetricTypeCounter, "{allocation}"},
-			"go.memory.gc.goal":     {MetricTypeGauge, "By"},
-			"go.goroutine.count":    {MetricTypeGauge, "{goroutine}"},
-			"go.processor.limit":    {MetricTypeGauge, "{thread}"},
-			"go.config.gogc":        {MetricTypeGauge, "%"},
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
 		}
 
-		for _, metric := range metrics {
-			expected, ok := expectedMetrics[metric.Name]
-			if !ok {
-				t.Errorf("Unexpected metric: %s", metric.Name)
-				continue
-			}
-			if metric.Type != expected.metric {
-				t.Errorf("Metric %s type = %v, want %v", metric.Name, metric.Type, expected.metric)
-			}
-			if metric.Unit != expected.unit {
-				t.Errorf("Metric %s unit = %v, want %v", metric.Name, metric.Unit, expected.unit)
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := map[string]AttributeRef{
+			"db.system.name": {RequirementLevel: RequirementLevelRequired},
+			"peer.service":   {RequirementLevel: RequirementLevelOptIn, Note: "Only recorded when `WithPeer`."},
+			"db.cached":      {RequirementLevel: RequirementLevelRecommended},
+			"db.namespace": {
+				RequirementLevel: RequirementLevelConditionallyRequired,
+				Condition:        `system == "postgresql" || system == "mysql"`,
+			},
+			"error.type": {
+				RequirementLevel: RequirementLevelConditionallyRequired,
+				Condition:        "err != nil",
+			},
+		}
+
+		group := findGroup(t, analysis.Groups, "testpkg.query.internal.span")
+		if got := len(group.Attributes); got != len(want) {
+			t.Fatalf("Group attributes count = %d, want %d", got, len(want))
+		}
+		for _, attr := range group.Attributes {
+			w := want[attr.Ref]
+			if attr.RequirementLevel != w.RequirementLevel || attr.Condition != w.Condition || attr.Note != w.Note {
+				t.Errorf("Attribute %s = %+v, want %+v", attr.Ref, attr, w)
 			}
 		}
 	})
+}
+
+func TestResolveAttributesAcrossFunctions(t *testing.T) {
+	t.Run("extractSpans - follows helpers, variables and option slices", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"example.com/testpkg/internal/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
 
-	t.Run("getSemConvMetrics - returns host metrics", func(t *testing.T) {
-		metrics := getSemConvMetrics("go.opentelemetry.io/contrib/instrumentation/host")
+func instrument(ctx context.Context, tracer trace.Tracer, method, route string) {
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(semconv.RequestAttrs(method)...),
+		trace.WithSpanKind(trace.SpanKindServer),
+	}
+	opt := trace.WithAttributes(attribute.String("custom.template", "index"))
+	opts = append(opts, opt)
 
-		if got := len(metrics); got != 5 {
-			t.Fatalf("Host metrics count = %d, want 5", got)
+	ctx, span := tracer.Start(ctx, "request", opts...)
+	defer span.End()
+
+	attrs, _ := semconv.RouteAttrs(route)
+	span.SetAttributes(attrs...)
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
 		}
 
-		expectedMetrics := map[string]struct {
-			metric MetricType
-			unit   string
-		}{
-			"process.cpu.time":          {MetricTypeCounter, "s"},
-			"system.cpu.time":           {MetricTypeCounter, "s"},
-			"system.memory.usage":       {MetricTypeGauge, "By"},
-			"system.memory.utilization": {MetricTypeGauge, "1"},
-			"system.network.io":         {MetricTypeCounter, "By"},
+		helper := `package semconv
+
+import "go.opentelemetry.io/otel/attribute"
+
+func RequestAttrs(method string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2)
+	attrs = append(attrs, attribute.String("http.request.method", method))
+	return append(attrs, methodOriginal(method))
+}
+
+func methodOriginal(method string) attribute.KeyValue {
+	return attribute.String("http.request.method_original", method)
+}
+
+func RouteAttrs(route string) (attrs []attribute.KeyValue, ok bool) {
+	attrs = []attribute.KeyValue{attribute.String("http.route", route)}
+	return
+}
+`
+		helperDir := filepath.Join(tmpDir, "internal", "semconv")
+		if err := os.MkdirAll(helperDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(helperDir, "semconv.go"), []byte(helper), 0644); err != nil {
+			t.Fatal(err)
 		}
 
-		for _, metric := range metrics {
-			expected, ok := expectedMetrics[metric.Name]
-			if !ok {
-				t.Errorf("Unexpected metric: %s", metric.Name)
-				continue
-			}
-			if metric.Type != expected.metric {
-				t.Errorf("Metric %s type = %v, want %v", metric.Name, metric.Type, expected.metric)
-			}
-			if metric.Unit != expected.unit {
-				t.Errorf("Metric %s unit = %v, want %v", metric.Name, metric.Unit, expected.unit)
-			}
-			if len(metric.Attributes) != 1 {
-				t.Errorf("Metric %s attributes count = %d, want 1", metric.Name, len(metric.Attributes))
-			}
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if analysis.Name != "testpkg" {
+			t.Errorf("Name = %q, want testpkg", analysis.Name)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		tel := analysis.Telemetry[0]
+		if got := len(tel.Spans); got != 1 {
+			t.Fatalf("Spans count = %d, want 1", got)
+		}
+
+		span := tel.Spans[0]
+		if span.Kind != SpanKindServer {
+			t.Errorf("Span kind = %q, want %q", span.Kind, SpanKindServer)
+		}
+
+		for _, name := range []string{
+			"http.request.method",
+			"http.request.method_original",
+			"http.route",
+			"custom.template",
+		} {
+			assertSpanHasAttribute(t, span.Attributes, name)
+		}
+	})
+}
+
+func TestExtractSpanNames(t *testing.T) {
+	t.Run("extractSpans - creates one span per span name", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const findSpanName = "mongo.find"
+
+func find(ctx context.Context, tracer trace.Tracer) {
+	ctx, span := tracer.Start(ctx, findSpanName, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(attribute.String("find.filter", "{}"))
+}
+
+func insert(ctx context.Context, tracer trace.Tracer, collection string) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("mongo.insert %s", collection),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("insert.count", 1)))
+	defer span.End()
+}
+`
+		filePath := filepath.Join(tmpDir, "test.go")
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		spans := analysis.Telemetry[0].Spans
+		if got := len(spans); got != 2 {
+			t.Fatalf("Spans count = %d, want 2", got)
+		}
+
+		find := spans[0]
+		if find.Name != "mongo.find" {
+			t.Errorf("Span name = %v, want mongo.find", find.Name)
+		}
+		if find.Kind != SpanKindClient {
+			t.Errorf("Span kind = %v, want CLIENT", find.Kind)
+		}
+		if find.Source != "test.go:13" {
+			t.Errorf("Span source = %v, want test.go:13", find.Source)
+		}
+		if got := len(find.Attributes); got != 1 || find.Attributes[0].Name != "find.filter" {
+			t.Errorf("Span %s attributes = %v, want [find.filter]", find.Name, find.Attributes)
+		}
+
+		insert := spans[1]
+		if insert.Name != "mongo.insert {collection}" {
+			t.Errorf("Span name = %v, want mongo.insert {collection}", insert.Name)
+		}
+		if got := len(insert.Attributes); got != 1 || insert.Attributes[0].Name != "insert.count" {
+			t.Errorf("Span %s attributes = %v, want [insert.count]", insert.Name, insert.Attributes)
+		}
+
+		groupIDs := make(map[string]bool)
+		for _, group := range analysis.Groups {
+			groupIDs[group.ID] = true
+		}
+		for _, want := range []string{"testpkg.mongo.find.client.span", "testpkg.mongo.insert_collection.client.span"} {
+			if !groupIDs[want] {
+				t.Errorf("Groups missing %s, got %v", want, groupIDs)
+			}
+		}
+	})
+
+	t.Run("extractSpans - defaults to internal spans and resolves WithSpanKind values", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serve(ctx context.Context, tracer trace.Tracer, srv *http.Server) {
+	kind := trace.SpanKindServer
+	ctx, span := tracer.Start(ctx, "serve", trace.WithSpanKind(kind))
+	defer span.End()
+
+	_ = srv.ListenAndServe()
+}
+
+func render(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "render")
+	defer span.End()
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		kinds := make(map[string]SpanKind)
+		for _, span := range analysis.Telemetry[0].Spans {
+			kinds[span.Name] = span.Kind
+		}
+		want := map[string]SpanKind{"serve": SpanKindServer, "render": SpanKindInternal}
+		if !reflect.DeepEqual(kinds, want) {
+			t.Errorf("Span kinds = %v, want %v", kinds, want)
+		}
+	})
+}
+
+func TestAnalyzeModulePackages(t *testing.T) {
+	t.Run("AnalyzePackage - attributes internal packages and skips examples and test helpers", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		files := map[string]string{
+			"test.go": `package testpkg
+
+import (
+	"context"
+
+	"example.com/testpkg/internal/request"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func handle(ctx context.Context, tracer trace.Tracer) {
+	request.Record(ctx, tracer)
+}
+`,
+			"internal/request/request.go": `package request
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Record(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "request", trace.WithAttributes(attribute.String("request.id", "1")))
+	defer span.End()
+}
+`,
+			"filters/filters.go": `package filters
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Filter(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "filter", trace.WithAttributes(attribute.Bool("filter.matched", true)))
+	defer span.End()
+}
+`,
+			"example/main.go": `package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func main() {
+	_, span := otel.Tracer("example").Start(context.Background(), "example", trace.WithAttributes(attribute.String("example.id", "1")))
+	defer span.End()
+}
+`,
+			"testutil/testutil.go": `package testutil
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Span(t *testing.T, tracer trace.Tracer) {
+	_, span := tracer.Start(context.Background(), "fixture", trace.WithAttributes(attribute.String("fixture.id", t.Name())))
+	defer span.End()
+}
+`,
+			"go.mod": `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`,
+		}
+		for name, content := range files {
+			path := filepath.Join(tmpDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if analysis.Name != "testpkg" {
+			t.Errorf("Name = %v, want testpkg", analysis.Name)
+		}
+
+		var spanNames []string
+		for _, tel := range analysis.Telemetry {
+			for _, span := range tel.Spans {
+				spanNames = append(spanNames, span.Name)
+			}
+		}
+		sort.Strings(spanNames)
+		if want := []string{"filter", "request"}; !reflect.DeepEqual(spanNames, want) {
+			t.Errorf("Span names = %v, want %v", spanNames, want)
+		}
+
+		request := findGroup(t, analysis.Groups, "testpkg.request.internal.span")
+		if got := request.Annotations["source"]; got != "internal/request/request.go:11" {
+			t.Errorf("request source = %v, want internal/request/request.go:11", got)
+		}
+		findGroup(t, analysis.Groups, "filters.filter.internal.span")
+
+		for _, group := range analysis.Groups {
+			if strings.Contains(group.ID, "example") || strings.Contains(group.ID, "fixture") {
+				t.Errorf("Groups include %s from a main or test helper package", group.ID)
+			}
+		}
+	})
+}
+
+func TestSemconvSpanAttributes(t *testing.T) {
+	t.Run("extractSpans - completes spans from the registry span group they match", func(t *testing.T) {
+		registryDir := t.TempDir()
+		registry := `groups:
+  - id: registry.http
+    type: attribute_group
+    attributes:
+      - id: http.request.method
+        type: string
+        examples: ["GET", "POST"]
+      - id: http.response.status_code
+        type: int
+      - id: http.route
+        type: string
+  - id: attributes.http.server
+    type: attribute_group
+    attributes:
+      - ref: http.response.status_code
+        requirement_level:
+          conditionally_required: If and only if one was sent.
+  - id: span.http.server
+    type: span
+    span_kind: server
+    extends: attributes.http.server
+    attributes:
+      - ref: http.request.method
+        requirement_level: required
+      - ref: http.route
+        requirement_level: recommended
+      - ref: url.scheme
+  - id: span.http.client
+    type: span
+    span_kind: client
+    attributes:
+      - ref: http.request.method
+        requirement_level: required
+  - id: span.db.client
+    type: span
+    span_kind: client
+    attributes:
+      - ref: db.query.text
+        requirement_level: recommended
+`
+		if err := os.WriteFile(filepath.Join(registryDir, "http.yaml"), []byte(registry), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := LoadSemconv(registryDir); err != nil {
+			t.Fatalf("LoadSemconv() error = %v", err)
+		}
+		t.Cleanup(func() {
+			_ = LoadSemconv("")
+		})
+
+		tmpDir := t.TempDir()
+
+		content := `package sqlproxy
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serve(tracer trace.Tracer, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "serve",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("http.request.method", r.Method)))
+	defer span.End()
+}
+
+func forward(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "forward", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+}
+
+func render(ctx context.Context, tracer trace.Tracer, name string) {
+	_, span := tracer.Start(ctx, "render",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("go.template", name)))
+	defer span.End()
+}
+
+var methodKey = semconv.HTTPRequestMethodKey
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/sqlproxy
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		server := findGroup(t, analysis.Groups, "sqlproxy.serve.server.span")
+		wantServer := []AttributeRef{
+			{Ref: "http.request.method", RequirementLevel: RequirementLevelRequired, Type: AttributeTypeString, Examples: []string{"GET", "POST"}},
+			{Ref: "http.route", RequirementLevel: RequirementLevelRecommended, Type: AttributeTypeString},
+			{Ref: "url.scheme", RequirementLevel: RequirementLevelRecommended, Type: AttributeTypeString},
+			{Ref: "http.response.status_code", RequirementLevel: RequirementLevelConditionallyRequired, Condition: "If and only if one was sent.", Type: AttributeTypeLong},
+		}
+		if !reflect.DeepEqual(server.Attributes, wantServer) {
+			t.Errorf("serve attributes = %+v, want %+v", server.Attributes, wantServer)
+		}
+
+		// The client span records nothing itself, so the namespaces of the
+		// semconv keys the package references select span.http.client.
+		client := findGroup(t, analysis.Groups, "sqlproxy.forward.client.span")
+		wantClient := []AttributeRef{
+			{Ref: "http.request.method", RequirementLevel: RequirementLevelRequired, Type: AttributeTypeString, Examples: []string{"GET", "POST"}},
+		}
+		if !reflect.DeepEqual(client.Attributes, wantClient) {
+			t.Errorf("forward attributes = %+v, want %+v", client.Attributes, wantClient)
+		}
+
+		// A span recording only its own attributes matches no span group and
+		// is left as it is.
+		render := findGroup(t, analysis.Groups, "sqlproxy.render.server.span")
+		wantRender := []AttributeRef{
+			{Ref: "go.template", RequirementLevel: RequirementLevelRequired, Type: AttributeTypeString},
+		}
+		if !reflect.DeepEqual(render.Attributes, wantRender) {
+			t.Errorf("render attributes = %+v, want %+v", render.Attributes, wantRender)
+		}
+	})
+}
+
+func TestExtractSpanAddEvent(t *testing.T) {
+	t.Run("extractSpans - captures AddEvent and RecordError as span events", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func instrument(ctx context.Context, tracer trace.Tracer) {
+	ctx, span := tracer.Start(ctx, "operation", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.AddEvent("error.occurred", trace.WithAttributes(
+		attribute.String("error.type", "timeout"),
+		attribute.String("error.message", "connection timeout"),
+	))
+	span.RecordError(errors.New("timeout"), trace.WithStackTrace(true))
+}
+`
+		filePath := filepath.Join(tmpDir, "test.go")
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		tel := analysis.Telemetry[0]
+		if got := len(tel.Spans); got != 1 {
+			t.Fatalf("Spans count = %d, want 1", got)
+		}
+
+		span := tel.Spans[0]
+		for _, attr := range span.Attributes {
+			if attr.Name == "error.type" || attr.Name == "error.message" {
+				t.Errorf("Event attribute %s should not be merged into the span", attr.Name)
+			}
+		}
+
+		if got := len(span.Events); got != 2 {
+			t.Fatalf("Events count = %d, want 2", got)
+		}
+
+		wantEvents := map[string][]string{
+			"error.occurred": {"error.type", "error.message"},
+			"exception":      {"exception.type", "exception.message", "exception.stacktrace"},
+		}
+		for _, event := range span.Events {
+			for _, name := range wantEvents[event.Name] {
+				assertSpanHasAttribute(t, event.Attributes, name)
+			}
+		}
+
+		groups := make(map[string]Group)
+		for _, group := range analysis.Groups {
+			groups[group.ID] = group
+		}
+
+		eventGroup, ok := groups["testpkg.event.error.occurred"]
+		if !ok {
+			t.Fatal("Expected event group testpkg.event.error.occurred")
+		}
+		if eventGroup.Type != "event" || eventGroup.EventName != "error.occurred" {
+			t.Errorf("Event group type = %q name = %q, want event error.occurred", eventGroup.Type, eventGroup.EventName)
+		}
+		if _, ok := groups["testpkg.event.exception"]; !ok {
+			t.Error("Expected event group testpkg.event.exception")
+		}
+
+		spanGroup, ok := groups["testpkg.operation.client.span"]
+		if !ok {
+			t.Fatal("Expected span group testpkg.operation.client.span")
+		}
+		if !reflect.DeepEqual(spanGroup.Events, []string{"error.occurred", "exception"}) {
+			t.Errorf("Span group events = %v, want [error.occurred exception]", spanGroup.Events)
+		}
+	})
+
+	t.Run("convertTelemetryToGroups - references registry events instead of redefining them", func(t *testing.T) {
+		registryDir := t.TempDir()
+		registry := `groups:
+  - id: event.exception
+    type: event
+    name: exception
+`
+		if err := os.WriteFile(filepath.Join(registryDir, "exception.yaml"), []byte(registry), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := LoadSemconv(registryDir); err != nil {
+			t.Fatalf("LoadSemconv() error = %v", err)
+		}
+		t.Cleanup(func() {
+			_ = LoadSemconv("")
+		})
+
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func instrument(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "operation")
+	defer span.End()
+
+	span.AddEvent("retry")
+	span.RecordError(errors.New("timeout"))
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		refs := make(map[string]string)
+		for _, event := range analysis.Telemetry[0].Spans[0].Events {
+			refs[event.Name] = event.SemconvRef
+		}
+		if want := map[string]string{"retry": "", "exception": "event.exception"}; !reflect.DeepEqual(refs, want) {
+			t.Errorf("Event refs = %v, want %v", refs, want)
+		}
+
+		var ids []string
+		for _, group := range analysis.Groups {
+			ids = append(ids, group.ID)
+			if group.Type == "span" && !reflect.DeepEqual(group.Events, []string{"retry", "exception"}) {
+				t.Errorf("Span group events = %v, want [retry exception]", group.Events)
+			}
+		}
+		sort.Strings(ids)
+		if want := []string{"testpkg.event.retry", "testpkg.operation.internal.span"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("Group IDs = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("extractSpans - traces span parameters to their callers", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func query(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "query")
+	defer span.End()
+
+	recordRetry(span)
+}
+
+func ping(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "ping")
+	defer span.End()
+
+	span.AddEvent("pong")
+}
+
+func recordRetry(span trace.Span) {
+	span.AddEvent("retry")
+}
+
+func handle(ctx context.Context) {
+	trace.SpanFromContext(ctx).AddEvent("handled")
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		events := make(map[string][]string)
+		for _, group := range analysis.Groups {
+			if group.Type == "span" {
+				events[group.ID] = group.Events
+			}
+		}
+		want := map[string][]string{
+			"testpkg.query.internal.span": {"retry"},
+			"testpkg.ping.internal.span":  {"pong"},
+			"testpkg.unknown.span":        {"handled"},
+		}
+		if !reflect.DeepEqual(events, want) {
+			t.Errorf("Span group events = %v, want %v", events, want)
+		}
+	})
+}
+
+func TestExtractSpanStatus(t *testing.T) {
+	t.Run("extractSpans - captures SetStatus codes and conditions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func status(code int) (codes.Code, string) {
+	if code < 100 || code >= 600 {
+		return codes.Error, fmt.Sprintf("Invalid HTTP status code %d", code)
+	}
+	if code >= 500 {
+		return codes.Error, ""
+	}
+	return codes.Unset, ""
+}
+
+func serve(ctx context.Context, tracer trace.Tracer, code int) {
+	ctx, span := tracer.Start(ctx, "serve", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	span.SetStatus(status(code))
+}
+
+func call(ctx context.Context, tracer trace.Tracer, err error) {
+	ctx, span := tracer.Start(ctx, "call", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		tel := analysis.Telemetry[0]
+
+		serve := findSpan(t, tel.Spans, "serve")
+		wantServe := []SpanStatus{
+			{Code: StatusCodeError, Description: true, Condition: "code < 100 || code >= 600"},
+			{Code: StatusCodeError, Condition: "code >= 500"},
+			{Code: StatusCodeUnset},
+		}
+		if !reflect.DeepEqual(serve.Status, wantServe) {
+			t.Errorf("serve status = %+v, want %+v", serve.Status, wantServe)
+		}
+		if recordsErrors(serve) {
+			t.Error("serve span should not record errors")
+		}
+
+		call := findSpan(t, tel.Spans, "call")
+		wantCall := []SpanStatus{
+			{Code: StatusCodeError, Description: true, Condition: "err != nil"},
+		}
+		if !reflect.DeepEqual(call.Status, wantCall) {
+			t.Errorf("call status = %+v, want %+v", call.Status, wantCall)
+		}
+		if !recordsErrors(call) {
+			t.Error("call span should record errors")
+		}
+
+		for _, group := range analysis.Groups {
+			if group.ID != "testpkg.call.client.span" {
+				continue
+			}
+			want := "Sets status `Error` when `err != nil` with a description.\nRecords errors as `exception` events."
+			if group.Note != want {
+				t.Errorf("Group note = %q, want %q", group.Note, want)
+			}
+		}
+	})
+
+	t.Run("extractSpans - records status and attributes only on the spans they are set on", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type labeler struct{}
+
+func (labeler) SetAttributes(kv ...attribute.KeyValue) {}
+
+func query(ctx context.Context, tracer trace.Tracer, err error) {
+	_, span := tracer.Start(ctx, "query")
+	defer span.End()
+
+	if err != nil {
+		fail(span, err)
+	}
+}
+
+func ping(ctx context.Context, tracer trace.Tracer, l labeler) {
+	_, span := tracer.Start(ctx, "ping")
+	defer span.End()
+
+	l.SetAttributes(attribute.String("labeler.name", "ping"))
+}
+
+func fail(span trace.Span, err error) {
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func handle(ctx context.Context) {
+	trace.SpanFromContext(ctx).SetStatus(codes.Ok, "")
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		statuses := make(map[string][]SpanStatus)
+		for _, span := range analysis.Telemetry[0].Spans {
+			statuses[span.Name] = span.Status
+			if len(span.Attributes) > 0 {
+				t.Errorf("Span %q attributes = %v, want none", span.Name, span.Attributes)
+			}
+		}
+		want := map[string][]SpanStatus{
+			"":      {{Code: StatusCodeOk}},
+			"query": {{Code: StatusCodeError, Description: true}},
+			"ping":  nil,
+		}
+		if !reflect.DeepEqual(statuses, want) {
+			t.Errorf("Span statuses = %+v, want %+v", statuses, want)
+		}
+	})
+}
+
+func TestExtractMetricUnit(t *testing.T) {
+	t.Run("extractMetrics - captures metric unit from WithUnit option", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"go.opentelemetry.io/otel/metric"
+)
+
+func instrument(meter metric.Meter) {
+	counter, _ := meter.Int64Counter("requests.total", metric.WithUnit("1"))
+	histogram, _ := meter.Float64Histogram("request.duration", metric.WithUnit("ms"))
+}
+`
+		filePath := filepath.Join(tmpDir, "test.go")
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		tel := analysis.Telemetry[0]
+		if got := len(tel.Metrics); got != 2 {
+			t.Fatalf("Metrics count = %d, want 2", got)
+		}
+
+		foundCounterUnit := false
+		foundHistogramUnit := false
+		for _, metric := range tel.Metrics {
+			if metric.Name == "requests.total" && metric.Unit == "1" {
+				foundCounterUnit = true
+			}
+			if metric.Name == "request.duration" && metric.Unit == "ms" {
+				foundHistogramUnit = true
+			}
+		}
+
+		if !foundCounterUnit {
+			t.Error("Expected requests.total metric with unit '1' not found")
+		}
+		if !foundHistogramUnit {
+			t.Error("Expected request.duration metric with unit 'ms' not found")
+		}
+	})
+}
+
+func TestExtractMetricOptions(t *testing.T) {
+	t.Run("extractMetrics - captures description and explicit bucket boundaries", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"go.opentelemetry.io/otel/metric"
+)
+
+const durationDescription = "Duration of processed requests."
+
+var durationBuckets = []float64{0.005, 0.01, 0.1, 1}
+
+func instrument(meter metric.Meter) {
+	_, _ = meter.Float64Histogram("request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription(durationDescription),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	_, _ = meter.Int64Histogram("request.size", metric.WithExplicitBucketBoundaries(0, 1024, 4096))
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		want := map[string][]float64{
+			"request.duration": {0.005, 0.01, 0.1, 1},
+			"request.size":     {0, 1024, 4096},
+		}
+
+		for _, metric := range analysis.Telemetry[0].Metrics {
+			if !reflect.DeepEqual(metric.BucketBoundaries, want[metric.Name]) {
+				t.Errorf("Metric %s boundaries = %v, want %v", metric.Name, metric.BucketBoundaries, want[metric.Name])
+			}
+		}
+
+		var found bool
+		for _, group := range analysis.Groups {
+			if group.ID != "testpkg.metric.request_duration" {
+				continue
+			}
+			found = true
+			if group.Brief != "Duration of processed requests." {
+				t.Errorf("Group brief = %q, want metric description", group.Brief)
+			}
+			if group.Unit != "s" {
+				t.Errorf("Group unit = %q, want s", group.Unit)
+			}
+			if !reflect.DeepEqual(group.Annotations["explicit_bucket_boundaries"], want["request.duration"]) {
+				t.Errorf("Group boundaries annotation = %v, want %v", group.Annotations["explicit_bucket_boundaries"], want["request.duration"])
+			}
+		}
+		if !found {
+			t.Error("Expected group testpkg.metric.request_duration")
+		}
+	})
+}
+
+func TestTypeBasedDetection(t *testing.T) {
+	t.Run("extractTelemetry - ignores Start and instrument methods on unrelated types", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type server struct{}
+
+func (server) Start(ctx context.Context, addr string) error { return nil }
+
+type registry struct{}
+
+func (registry) Int64Counter(name string) int { return 0 }
+
+func instrument(ctx context.Context, tracer trace.Tracer, meter metric.Meter) {
+	_ = server{}.Start(ctx, "localhost:8080")
+	_ = registry{}.Int64Counter("registry.lookups")
+
+	_, span := tracer.Start(ctx, "operation")
+	defer span.End()
+
+	_, _ = meter.Int64ObservableGauge("queue.depth")
+	_, _ = meter.Float64Histogram("request.duration")
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		tel := analysis.Telemetry[0]
+		if got := len(tel.Spans); got != 1 {
+			t.Fatalf("Spans count = %d, want 1", got)
+		}
+		if tel.Spans[0].Name != "operation" {
+			t.Errorf("Span name = %q, want operation", tel.Spans[0].Name)
+		}
+
+		want := map[string]struct {
+			metricType MetricType
+			instrument string
+		}{
+			"queue.depth":      {MetricTypeGauge, "Int64ObservableGauge"},
+			"request.duration": {MetricTypeHistogram, "Float64Histogram"},
+		}
+
+		if got := len(tel.Metrics); got != len(want) {
+			t.Fatalf("Metrics count = %d, want %d", got, len(want))
+		}
+
+		for _, metric := range tel.Metrics {
+			expected, ok := want[metric.Name]
+			if !ok {
+				t.Errorf("Unexpected metric %q", metric.Name)
+				continue
+			}
+			if metric.Type != expected.metricType {
+				t.Errorf("Metric %s type = %q, want %q", metric.Name, metric.Type, expected.metricType)
+			}
+			if metric.Instrument != expected.instrument {
+				t.Errorf("Metric %s instrument = %q, want %q", metric.Name, metric.Instrument, expected.instrument)
+			}
+		}
+	})
+}
+
+func TestExtractMetricAttributes(t *testing.T) {
+	t.Run("extractMetrics - collects attributes from Add, Record and Observe calls", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type instruments struct {
+	duration metric.Float64Histogram
+	queue    metric.Int64ObservableGauge
+}
+
+func newInstruments(meter metric.Meter) (*instruments, error) {
+	inst := &instruments{}
+	var err error
+	inst.duration, err = meter.Float64Histogram("request.duration")
+	if err != nil {
+		return nil, err
+	}
+	inst.queue, err = meter.Int64ObservableGauge("queue.depth")
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveInt64(inst.queue, 3, metric.WithAttributes(attribute.String("queue.name", "default")))
+		return nil
+	}, inst.queue)
+	return inst, err
+}
+
+func record(ctx context.Context, meter metric.Meter, inst *instruments, route string) {
+	counter, _ := meter.Int64Counter("requests.total")
+	set := attribute.NewSet(attribute.String("http.route", route), attribute.Bool("cache.hit", true))
+	counter.Add(ctx, 1, metric.WithAttributeSet(set))
+
+	opts := []metric.RecordOption{metric.WithAttributes(attribute.Int("http.response.status_code", 200))}
+	inst.duration.Record(ctx, 1.5, opts...)
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		want := map[string]map[string]AttributeType{
+			"requests.total": {
+				"http.route": AttributeTypeString,
+				"cache.hit":  AttributeTypeBoolean,
+			},
+			"request.duration": {
+				"http.response.status_code": AttributeTypeLong,
+			},
+			"queue.depth": {
+				"queue.name": AttributeTypeString,
+			},
+		}
+
+		metrics := analysis.Telemetry[0].Metrics
+		if got := len(metrics); got != len(want) {
+			t.Fatalf("Metrics count = %d, want %d", got, len(want))
+		}
+
+		for _, metric := range metrics {
+			attrs := make(map[string]AttributeType)
+			for _, attr := range metric.Attributes {
+				attrs[attr.Name] = attr.Type
+			}
+			for name, attrType := range want[metric.Name] {
+				if attrs[name] != attrType {
+					t.Errorf("Metric %s attribute %s type = %q, want %q", metric.Name, name, attrs[name], attrType)
+				}
+			}
+		}
+
+		var found bool
+		for _, group := range analysis.Groups {
+			if group.ID != "testpkg.metric.requests_total" {
+				continue
+			}
+			found = true
+			if got := len(group.Attributes); got != 2 {
+				t.Errorf("Group %s attributes = %d, want 2", group.ID, got)
+			}
+		}
+		if !found {
+			t.Error("Expected group testpkg.metric.requests_total")
+		}
+	})
+}
+
+func TestExtractAsyncInstruments(t *testing.T) {
+	t.Run("extractMetrics - extracts observable instruments and callback attributes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	AttributeCPUTimeUser   = attribute.NewSet(attribute.String("state", "user"))
+	AttributeCPUTimeSystem = attribute.NewSet(attribute.String("state", "system"))
+)
+
+type host struct {
+	processCPUTime metric.Float64ObservableCounter
+}
+
+func (h *host) register(meter metric.Meter) error {
+	var err error
+	if h.processCPUTime, err = meter.Float64ObservableCounter(
+		"process.cpu.time",
+		metric.WithUnit("s"),
+	); err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveFloat64(h.processCPUTime, 1, metric.WithAttributeSet(AttributeCPUTimeUser))
+		o.ObserveFloat64(h.processCPUTime, 2, metric.WithAttributeSet(AttributeCPUTimeSystem))
+		return nil
+	}, h.processCPUTime)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.Int64ObservableUpDownCounter(
+		"system.network.connections",
+		metric.WithInt64Callback(observeConnections),
+	)
+	return err
+}
+
+func observeConnections(ctx context.Context, o metric.Int64Observer) error {
+	o.Observe(3, metric.WithAttributes(attribute.String("network.transport", "tcp")))
+	return nil
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		want := map[string]struct {
+			metricType MetricType
+			attribute  string
+		}{
+			"process.cpu.time":           {MetricTypeCounter, "state"},
+			"system.network.connections": {MetricTypeUpDownCounter, "network.transport"},
+		}
+
+		metrics := analysis.Telemetry[0].Metrics
+		if got := len(metrics); got != len(want) {
+			t.Fatalf("Metrics count = %d, want %d", got, len(want))
+		}
+
+		for _, metric := range metrics {
+			expected := want[metric.Name]
+			if metric.Type != expected.metricType {
+				t.Errorf("Metric %s type = %q, want %q", metric.Name, metric.Type, expected.metricType)
+			}
+			if !metric.Observable {
+				t.Errorf("Metric %s should be observable", metric.Name)
+			}
+			if got := len(metric.Attributes); got != 1 {
+				t.Errorf("Metric %s attributes count = %d, want 1", metric.Name, got)
+			}
+			assertSpanHasAttribute(t, metric.Attributes, expected.attribute)
+		}
+
+		for _, group := range analysis.Groups {
+			if group.Type == "metric" && group.Annotations["observable"] != true {
+				t.Errorf("Group %s should be annotated as observable", group.ID)
+			}
+		}
+	})
+}
+
+func TestExtractConvInstruments(t *testing.T) {
+	t.Run("extractMetrics - records semconv helper instruments the library constructs", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		files := map[string]string{
+			"test.go": `package testpkg
+
+import (
+	"context"
+
+	"example.com/testpkg/internal/semconv"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/goconv"
+)
+
+func newMetrics(meter metric.Meter) error {
+	if _, err := goconv.NewMemoryUsed(meter); err != nil {
+		return err
+	}
+	server := semconv.NewServer(meter)
+	server.Record(context.Background(), 1.5)
+	return nil
+}
+`,
+			"internal/semconv/semconv.go": `package semconv
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/httpconv"
+)
+
+type Server struct {
+	duration httpconv.ServerRequestDuration
+}
+
+func NewServer(meter metric.Meter) Server {
+	duration, _ := httpconv.NewServerRequestDuration(meter)
+	return Server{duration: duration}
+}
+
+func (s Server) Record(ctx context.Context, seconds float64) {
+	s.duration.Record(ctx, seconds, "GET", "https")
+}
+
+func NewClient(meter metric.Meter) httpconv.ClientRequestDuration {
+	duration, _ := httpconv.NewClientRequestDuration(meter)
+	return duration
+}
+`,
+			"go.mod": `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`,
+		}
+		for name, content := range files {
+			path := filepath.Join(tmpDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+
+		// NewClient is never reached from the public package.
+		expectedMetrics := map[string]Metric{
+			"go.memory.used": {
+				Name:        "go.memory.used",
+				Type:        MetricTypeUpDownCounter,
+				Instrument:  "Int64ObservableUpDownCounter",
+				Observable:  true,
+				Description: "Memory used by the Go runtime.",
+				Unit:        "By",
+				SemconvRef:  "metric.go.memory.used",
+			},
+			"http.server.request.duration": {
+				Name:        "http.server.request.duration",
+				Type:        MetricTypeHistogram,
+				Instrument:  "Float64Histogram",
+				Description: "Duration of HTTP server requests.",
+				Unit:        "s",
+				SemconvRef:  "metric.http.server.request.duration",
+			},
+		}
+
+		metrics := analysis.Telemetry[0].Metrics
+		if got := len(metrics); got != len(expectedMetrics) {
+			t.Fatalf("Metrics count = %d, want %d: %+v", got, len(expectedMetrics), metrics)
+		}
+		for _, metric := range metrics {
+			if want, ok := expectedMetrics[metric.Name]; !ok || !reflect.DeepEqual(metric, want) {
+				t.Errorf("Metric = %+v, want %+v", metric, want)
+			}
+		}
+
+		for _, group := range analysis.Groups {
+			if group.Type == "metric" {
+				t.Errorf("Group %s duplicates a semconv metric", group.ID)
+			}
+		}
+	})
+	t.Run("extractMetrics - keeps helper instruments of methods called through interfaces", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		files := map[string]string{
+			"test.go": `package testpkg
+
+import (
+	"context"
+
+	"example.com/testpkg/internal/handler"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler interface {
+	Handle(ctx context.Context)
+}
+
+func New(tracer trace.Tracer, meter metric.Meter) Handler {
+	return handler.New(tracer, meter)
+}
+`,
+			"internal/handler/handler.go": `package handler
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type H struct {
+	tracer trace.Tracer
+	meter  metric.Meter
+}
+
+func New(tracer trace.Tracer, meter metric.Meter) *H {
+	return &H{tracer: tracer, meter: meter}
+}
+
+func (h *H) Handle(ctx context.Context) {
+	_, span := h.tracer.Start(ctx, "handle")
+	defer span.End()
+
+	duration, _ := httpconv.NewServerRequestDuration(h.meter)
+	duration.Record(ctx, 1, "GET", "https")
+}
+
+func unused(ctx context.Context, tracer trace.Tracer, meter metric.Meter) {
+	_, span := tracer.Start(ctx, "unused")
+	defer span.End()
+
+	_, _ = httpconv.NewClientRequestDuration(meter)
+}
+`,
+			"go.mod": `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`,
+		}
+		for name, content := range files {
+			path := filepath.Join(tmpDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if got := len(analysis.Telemetry); got != 1 {
+			t.Fatalf("Telemetry count = %d, want 1", got)
+		}
+		tel := analysis.Telemetry[0]
+
+		// Spans are never pruned; only the unreached client helper is.
+		var spanNames []string
+		for _, span := range tel.Spans {
+			spanNames = append(spanNames, span.Name)
+		}
+		sort.Strings(spanNames)
+		if want := []string{"handle", "unused"}; !reflect.DeepEqual(spanNames, want) {
+			t.Errorf("Span names = %v, want %v", spanNames, want)
+		}
+
+		var metricNames []string
+		for _, metric := range tel.Metrics {
+			metricNames = append(metricNames, metric.Name)
+		}
+		if want := []string{"http.server.request.duration"}; !reflect.DeepEqual(metricNames, want) {
+			t.Errorf("Metric names = %v, want %v", metricNames, want)
 		}
 	})
 }
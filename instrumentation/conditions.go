@@ -0,0 +1,281 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// maxGuardDepth bounds how far condition values are followed through
+// variables, fields and helper functions.
+const maxGuardDepth = 8
+
+// setOptionGuards records the config fields assigned by each With* option
+// constructor in pkg so conditions on them can be labelled with the option.
+func (r *attrResolver) setOptionGuards(pkg *packages.Package) {
+	if r.options == nil {
+		r.options = make(map[types.Object]string)
+	}
+	if pkg.TypesInfo == nil {
+		return
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || !isOptionConstructor(funcDecl, pkg) {
+				continue
+			}
+			for _, field := range optionFields(funcDecl, pkg) {
+				if _, exists := r.options[field]; !exists {
+					r.options[field] = funcDecl.Name.Name
+				}
+			}
+		}
+	}
+}
+
+// guard labels the condition under which node is evaluated when it sits in
+// the body of an if statement guarded by an environment variable
+// (e.g. OTEL_SEMCONV_STABILITY_OPT_IN=http/dup) or a configuration option
+// (e.g. WithPublicEndpoint). Unguarded nodes and else branches return "".
+func (r *attrResolver) guard(node ast.Node, pkg *packages.Package) string {
+	for _, file := range pkg.Syntax {
+		if node.Pos() < file.FileStart || node.Pos() > file.FileEnd {
+			continue
+		}
+
+		path, _ := astutil.PathEnclosingInterval(file, node.Pos(), node.End())
+		for i, n := range path {
+			switch stmt := n.(type) {
+			case *ast.IfStmt:
+				if i > 0 && path[i-1] == stmt.Body {
+					if label := r.condLabel(stmt.Cond, pkg, 0); label != "" {
+						return label
+					}
+				}
+			case *ast.FuncDecl:
+				return ""
+			}
+		}
+	}
+
+	return ""
+}
+
+// condLabel renders a condition as NAME=value for environment variables or
+// as the With* option setting the config field it tests.
+func (r *attrResolver) condLabel(expr ast.Expr, pkg *packages.Package, depth int) string {
+	if depth > maxGuardDepth || pkg.TypesInfo == nil {
+		return ""
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.condLabel(e.X, pkg, depth+1)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND, token.LOR:
+			if label := r.condLabel(e.X, pkg, depth+1); label != "" {
+				return label
+			}
+			return r.condLabel(e.Y, pkg, depth+1)
+		case token.EQL:
+			if label := r.envComparison(e.X, e.Y, pkg, depth); label != "" {
+				return label
+			}
+			return r.envComparison(e.Y, e.X, pkg, depth)
+		case token.NEQ:
+			if isNilIdent(e.Y) {
+				return r.optionLabel(e.X, pkg)
+			}
+		}
+	case *ast.Ident:
+		if v, ok := identObject(e, pkg).(*types.Var); ok {
+			for _, leaf := range r.expandVar(v) {
+				if label := r.condLabel(leaf.expr, leaf.pkg, depth+1); label != "" {
+					return label
+				}
+			}
+		}
+	case *ast.SelectorExpr:
+		if label := r.optionLabel(e, pkg); label != "" {
+			return label
+		}
+		if v, ok := exprObject(e, pkg).(*types.Var); ok && v.IsField() {
+			for _, leaf := range r.fieldValues(v) {
+				if label := r.condLabel(leaf.expr, leaf.pkg, depth+1); label != "" {
+					return label
+				}
+			}
+		}
+	case *ast.CallExpr:
+		fn := calledFuncExpr(e.Fun, pkg)
+		if fn == nil {
+			return ""
+		}
+		switch fn.FullName() {
+		case "strconv.ParseBool":
+			if len(e.Args) == 1 {
+				if name := r.envName(e.Args[0], pkg, depth+1); name != "" {
+					return name + "=true"
+				}
+			}
+			return ""
+		case "strings.EqualFold", "strings.Contains":
+			if len(e.Args) == 2 {
+				return r.envComparison(e.Args[0], e.Args[1], pkg, depth)
+			}
+			return ""
+		}
+		if src, ok := r.funcs[fn.Origin().FullName()]; ok {
+			for _, leaf := range r.expandFunc(src, 0) {
+				if label := r.condLabel(leaf.expr, leaf.pkg, depth+1); label != "" {
+					return label
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func (r *attrResolver) envComparison(env, value ast.Expr, pkg *packages.Package, depth int) string {
+	constValue, ok := constantString(value, pkg)
+	if !ok {
+		return ""
+	}
+	if name := r.envName(env, pkg, depth+1); name != "" {
+		return name + "=" + constValue
+	}
+	return ""
+}
+
+func (r *attrResolver) optionLabel(expr ast.Expr, pkg *packages.Package) string {
+	if obj := exprObject(expr, pkg); obj != nil {
+		return r.options[obj]
+	}
+	return ""
+}
+
+// envName returns the environment variable an expression's value is read from.
+func (r *attrResolver) envName(expr ast.Expr, pkg *packages.Package, depth int) string {
+	if depth > maxGuardDepth || pkg.TypesInfo == nil {
+		return ""
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.envName(e.X, pkg, depth+1)
+	case *ast.CallExpr:
+		fn := calledFuncExpr(e.Fun, pkg)
+		if fn == nil || len(e.Args) == 0 {
+			return ""
+		}
+		switch fn.FullName() {
+		case "os.Getenv", "os.LookupEnv":
+			if names := r.stringValues(e.Args[0], pkg); len(names) > 0 {
+				return names[0]
+			}
+		case "strings.ToLower", "strings.ToUpper", "strings.TrimSpace", "strings.Split", "strings.Fields":
+			return r.envName(e.Args[0], pkg, depth+1)
+		}
+	case *ast.Ident:
+		if v, ok := identObject(e, pkg).(*types.Var); ok {
+			for _, leaf := range r.expandVar(v) {
+				if name := r.envName(leaf.expr, leaf.pkg, depth+1); name != "" {
+					return name
+				}
+			}
+		}
+	case *ast.SelectorExpr:
+		if v, ok := exprObject(e, pkg).(*types.Var); ok && v.IsField() {
+			for _, leaf := range r.fieldValues(v) {
+				if name := r.envName(leaf.expr, leaf.pkg, depth+1); name != "" {
+					return name
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// splitTelemetry builds the default telemetry, holding everything emitted
+// unconditionally, followed by one entry per guard condition holding the
+// default telemetry plus what that condition adds.
+func splitTelemetry(spans []Span, metrics []Metric) []Telemetry {
+	conditions := make(map[string]bool)
+	for _, span := range spans {
+		conditions[span.When] = true
+		for _, attr := range span.Attributes {
+			conditions[attr.When] = true
+		}
+	}
+	for _, metric := range metrics {
+		conditions[metric.When] = true
+		for _, attr := range metric.Attributes {
+			conditions[attr.When] = true
+		}
+	}
+	delete(conditions, "")
+
+	var whens []string
+	for when := range conditions {
+		whens = append(whens, when)
+	}
+	sort.Strings(whens)
+
+	telemetry := []Telemetry{{
+		When:    "default",
+		Spans:   spanVariant(spans, ""),
+		Metrics: metricVariant(metrics, ""),
+	}}
+	for _, when := range whens {
+		telemetry = append(telemetry, Telemetry{
+			When:    when,
+			Spans:   spanVariant(spans, when),
+			Metrics: metricVariant(metrics, when),
+		})
+	}
+
+	return telemetry
+}
+
+func spanVariant(spans []Span, when string) []Span {
+	var variant []Span
+	for _, span := range spans {
+		if span.When != "" && span.When != when {
+			continue
+		}
+		span.Attributes = attributeVariant(span.Attributes, when)
+		variant = append(variant, span)
+	}
+	return variant
+}
+
+func metricVariant(metrics []Metric, when string) []Metric {
+	var variant []Metric
+	for _, metric := range metrics {
+		if metric.When != "" && metric.When != when {
+			continue
+		}
+		metric.Attributes = attributeVariant(metric.Attributes, when)
+		variant = append(variant, metric)
+	}
+	return variant
+}
+
+func attributeVariant(attrs []Attribute, when string) []Attribute {
+	var variant []Attribute
+	for _, attr := range attrs {
+		if attr.When == "" || attr.When == when {
+			variant = append(variant, attr)
+		}
+	}
+	return variant
+}
@@ -0,0 +1,161 @@
+package instrumentation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConditionalTelemetry(t *testing.T) {
+	t.Run("extractTelemetry - splits telemetry guarded by env vars and options", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+
+	"example.com/testpkg/internal/semconv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type config struct {
+	PublicEndpoint bool
+}
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// WithPublicEndpoint marks the endpoint as public.
+func WithPublicEndpoint() Option {
+	return optionFunc(func(c *config) {
+		c.PublicEndpoint = true
+	})
+}
+
+func handle(ctx context.Context, tracer trace.Tracer, cfg config, server semconv.HTTPServer, method string) {
+	ctx, span := tracer.Start(ctx, "request",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(server.RequestAttrs(method)...),
+	)
+	defer span.End()
+
+	if cfg.PublicEndpoint {
+		span.SetAttributes(attribute.Bool("http.public_endpoint", true))
+	}
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		helper := `package semconv
+
+import (
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const OTelSemConvStabilityOptIn = "OTEL_SEMCONV_STABILITY_OPT_IN"
+
+type HTTPServer struct {
+	duplicate bool
+}
+
+func NewHTTPServer() HTTPServer {
+	env := strings.ToLower(os.Getenv(OTelSemConvStabilityOptIn))
+	return HTTPServer{duplicate: env == "http/dup"}
+}
+
+func (s HTTPServer) RequestAttrs(method string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("http.request.method", method)}
+	if s.duplicate {
+		attrs = append(attrs, attribute.String("http.method", method))
+	}
+	return attrs
+}
+`
+		helperDir := filepath.Join(tmpDir, "internal", "semconv")
+		if err := os.MkdirAll(helperDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(helperDir, "semconv.go"), []byte(helper), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := map[string][]string{
+			"default":                                {"http.request.method"},
+			"OTEL_SEMCONV_STABILITY_OPT_IN=http/dup": {"http.request.method", "http.method"},
+			"WithPublicEndpoint":                     {"http.request.method", "http.public_endpoint"},
+		}
+
+		if got := len(analysis.Telemetry); got != len(want) {
+			t.Fatalf("Telemetry count = %d, want %d", got, len(want))
+		}
+
+		if analysis.Telemetry[0].When != "default" {
+			t.Errorf("First telemetry when = %q, want default", analysis.Telemetry[0].When)
+		}
+
+		for _, tel := range analysis.Telemetry {
+			attrs, ok := want[tel.When]
+			if !ok {
+				t.Errorf("Unexpected telemetry condition %q", tel.When)
+				continue
+			}
+			span := findSpan(t, tel.Spans, "request")
+			if got := len(span.Attributes); got != len(attrs) {
+				t.Errorf("Telemetry %s attributes count = %d, want %d", tel.When, got, len(attrs))
+			}
+			for _, name := range attrs {
+				assertSpanHasAttribute(t, span.Attributes, name)
+			}
+		}
+
+		var found bool
+		for _, group := range analysis.Groups {
+			if group.ID != "testpkg.request.server.span" {
+				continue
+			}
+			found = true
+			for _, attr := range group.Attributes {
+				if attr.Ref == "http.method" && (attr.RequirementLevel != RequirementLevelConditionallyRequired || attr.Condition != "OTEL_SEMCONV_STABILITY_OPT_IN=http/dup") {
+					t.Errorf("http.method = %+v, want conditionally required on OTEL_SEMCONV_STABILITY_OPT_IN=http/dup", attr)
+				}
+				if attr.Ref == "http.request.method" && attr.Note != "" {
+					t.Errorf("http.request.method note = %q, want empty", attr.Note)
+				}
+			}
+		}
+		if !found {
+			t.Error("Expected group testpkg.request.server.span")
+		}
+	})
+}
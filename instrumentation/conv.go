@@ -0,0 +1,134 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// extractConvInstrument records the semconv metric created by a semconv
+// helper constructor such as httpconv.NewServerRequestDuration or
+// goconv.NewMemoryUsed.
+func extractConvInstrument(callExpr *ast.CallExpr, pkg *packages.Package, resolver *attrResolver, metricMap map[string]*Metric) *Metric {
+	fn := calledFuncExpr(callExpr.Fun, pkg)
+	if fn == nil || fn.Pkg() == nil || semconvVersion(fn.Pkg().Path()) == "" || !strings.HasPrefix(fn.Name(), "New") {
+		return nil
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() != nil || sig.Results().Len() == 0 {
+		return nil
+	}
+	named, ok := sig.Results().At(0).Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	instrument := convInstrumentType(named)
+	metricType := mapMetricType(instrument)
+	if metricType == "" {
+		return nil
+	}
+
+	methods := resolver.convMethods(named, pkg.Fset)
+	metricName := methods["Name"]
+	if metricName == "" {
+		return nil
+	}
+
+	if _, exists := metricMap[metricName]; !exists {
+		metric := &Metric{
+			Name:        metricName,
+			Type:        metricType,
+			Instrument:  instrument,
+			Observable:  strings.Contains(instrument, "Observable"),
+			Unit:        methods["Unit"],
+			Description: methods["Description"],
+			SemconvRef:  semconvMetricRef(metricName),
+			When:        resolver.guard(callExpr, pkg),
+		}
+		extractMetricOptions(metric, callExpr, pkg, resolver)
+		metricMap[metricName] = metric
+	}
+
+	return metricMap[metricName]
+}
+
+// convInstrumentType returns the metric.Meter instrument a semconv helper
+// type embeds, e.g. Float64Histogram.
+func convInstrumentType(named *types.Named) string {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return ""
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		embedded, ok := field.Type().(*types.Named)
+		if !field.Embedded() || !ok || embedded.Obj().Pkg() == nil || embedded.Obj().Pkg().Path() != metricPkgPath {
+			continue
+		}
+		return embedded.Obj().Name()
+	}
+	return ""
+}
+
+// convMethods returns the string constants the Name, Unit and Description
+// methods of a semconv helper type return. Dependencies are loaded without
+// syntax, so the helper's source file is parsed from the position recorded
+// in its export data.
+func (r *attrResolver) convMethods(named *types.Named, fset *token.FileSet) map[string]string {
+	filename := fset.Position(named.Obj().Pos()).Filename
+	if filename == "" {
+		return nil
+	}
+
+	if r.convFiles == nil {
+		r.convFiles = make(map[string]*ast.File)
+	}
+	file, ok := r.convFiles[filename]
+	if !ok {
+		file, _ = parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+		r.convFiles[filename] = file
+	}
+	if file == nil {
+		return nil
+	}
+
+	methods := make(map[string]string)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 || funcDecl.Body == nil || len(funcDecl.Body.List) != 1 {
+			continue
+		}
+		if recv, ok := funcDecl.Recv.List[0].Type.(*ast.Ident); !ok || recv.Name != named.Obj().Name() {
+			continue
+		}
+		ret, ok := funcDecl.Body.List[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		lit, ok := ret.Results[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		if value, err := strconv.Unquote(lit.Value); err == nil {
+			methods[funcDecl.Name.Name] = value
+		}
+	}
+	return methods
+}
+
+// semconvMetricRef returns the ID of the registry metric group for a metric
+// name, falling back to the registry's metric.<name> convention when the
+// registry is not loaded.
+func semconvMetricRef(name string) string {
+	if metric, ok := GetSemconvMetric(name); ok && metric.ID != "" {
+		return metric.ID
+	}
+	return "metric." + name
+}
@@ -0,0 +1,166 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// envLookups are the functions reading environment variables.
+var envLookups = map[string]bool{
+	"os.Getenv":    true,
+	"os.LookupEnv": true,
+}
+
+// extractEnvVars lists the environment variables read through os.Getenv and
+// os.LookupEnv anywhere in the module, with the values compared against
+// where they can be determined statically.
+func extractEnvVars(pkgs []*packages.Package, resolver *attrResolver) []EnvVar {
+	envMap := make(map[string]*EnvVar)
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				callExpr, ok := n.(*ast.CallExpr)
+				if !ok || len(callExpr.Args) == 0 {
+					return true
+				}
+
+				fn := calledFuncExpr(callExpr.Fun, pkg)
+				if fn == nil || !envLookups[fn.FullName()] {
+					return true
+				}
+
+				values := envValues(callExpr, file, pkg, make(map[types.Object]bool))
+				for _, name := range resolver.stringValues(callExpr.Args[0], pkg) {
+					env, ok := envMap[name]
+					if !ok {
+						env = &EnvVar{Name: name, Source: callSite(callExpr, pkg)}
+						envMap[name] = env
+					}
+					env.Values = mergeStrings(env.Values, values)
+				}
+
+				return true
+			})
+		}
+	}
+
+	var envVars []EnvVar
+	for _, env := range envMap {
+		sort.Strings(env.Values)
+		envVars = append(envVars, *env)
+	}
+
+	sort.Slice(envVars, func(i, j int) bool {
+		return envVars[i].Name < envVars[j].Name
+	})
+
+	return envVars
+}
+
+// envValues follows the uses of an environment variable value and collects
+// the constants it is compared against, e.g. v == "true", switch v { case "http": },
+// strings.EqualFold(v, "dup") or strconv.ParseBool(v).
+func envValues(expr ast.Expr, file *ast.File, pkg *packages.Package, seen map[types.Object]bool) []string {
+	path, _ := astutil.PathEnclosingInterval(file, expr.Pos(), expr.End())
+	if len(path) < 2 {
+		return nil
+	}
+
+	switch parent := path[1].(type) {
+	case *ast.ParenExpr:
+		return envValues(parent, file, pkg, seen)
+	case *ast.BinaryExpr:
+		if parent.Op != token.EQL && parent.Op != token.NEQ {
+			return nil
+		}
+		other := parent.X
+		if other == expr {
+			other = parent.Y
+		}
+		if value, ok := constantString(other, pkg); ok {
+			return []string{value}
+		}
+	case *ast.CallExpr:
+		fn := calledFuncExpr(parent.Fun, pkg)
+		if fn == nil {
+			return nil
+		}
+		switch fn.FullName() {
+		case "strconv.ParseBool":
+			return []string{"true", "false"}
+		case "strings.EqualFold", "strings.Contains", "strings.HasPrefix", "strings.HasSuffix":
+			var values []string
+			for _, arg := range parent.Args {
+				if value, ok := constantString(arg, pkg); ok && arg != expr {
+					values = append(values, value)
+				}
+			}
+			return values
+		case "strings.ToLower", "strings.ToUpper", "strings.TrimSpace", "strings.Split", "strings.Fields":
+			return envValues(parent, file, pkg, seen)
+		}
+	case *ast.SwitchStmt:
+		if parent.Tag != expr {
+			return nil
+		}
+		var values []string
+		for _, stmt := range parent.Body.List {
+			clause, ok := stmt.(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+			for _, c := range clause.List {
+				if value, ok := constantString(c, pkg); ok {
+					values = append(values, value)
+				}
+			}
+		}
+		return values
+	case *ast.RangeStmt:
+		if parent.X == expr && parent.Value != nil {
+			return envVarUses(identObject(parent.Value, pkg), file, pkg, seen)
+		}
+	case *ast.AssignStmt:
+		for i, rhs := range parent.Rhs {
+			if rhs == expr && i < len(parent.Lhs) {
+				return envVarUses(identObject(parent.Lhs[i], pkg), file, pkg, seen)
+			}
+		}
+	case *ast.ValueSpec:
+		for i, value := range parent.Values {
+			if value == expr && i < len(parent.Names) {
+				return envVarUses(identObject(parent.Names[i], pkg), file, pkg, seen)
+			}
+		}
+	}
+
+	return nil
+}
+
+// envVarUses collects envValues for every use of a variable holding an
+// environment variable value.
+func envVarUses(obj types.Object, file *ast.File, pkg *packages.Package, seen map[types.Object]bool) []string {
+	if obj == nil || seen[obj] {
+		return nil
+	}
+	seen[obj] = true
+
+	var values []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if ok && pkg.TypesInfo.Uses[ident] == obj {
+			values = append(values, envValues(ident, file, pkg, seen)...)
+		}
+		return true
+	})
+	return values
+}
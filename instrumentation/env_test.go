@@ -0,0 +1,141 @@
+package instrumentation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractEnvVars(t *testing.T) {
+	t.Run("extractEnvVars - lists environment variables and accepted values", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"example.com/testpkg/internal/x"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const semconvOptIn = "OTEL_SEMCONV_STABILITY_OPT_IN"
+
+var traceEnv = "OTEL_TESTPKG_TRACE"
+
+func debug() bool {
+	name := "OTEL_TESTPKG_DEBUG"
+	return os.Getenv(name) == "1" || os.Getenv(traceEnv) == "1"
+}
+
+func duplicate() bool {
+	for _, opt := range strings.Split(os.Getenv(semconvOptIn), ",") {
+		if strings.TrimSpace(opt) == "http/dup" {
+			return true
+		}
+	}
+	return false
+}
+
+func mode() string {
+	v, ok := os.LookupEnv("OTEL_TESTPKG_MODE")
+	if !ok {
+		return "default"
+	}
+	switch strings.ToLower(v) {
+	case "fast", "safe":
+		return v
+	}
+	return "default"
+}
+
+func instrument(ctx context.Context, tracer trace.Tracer) {
+	if x.Verbose.Enabled() {
+		_, span := tracer.Start(ctx, "operation")
+		span.End()
+	}
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		feature := `package x
+
+import (
+	"os"
+	"strconv"
+)
+
+var Verbose = newFeature("VERBOSE", false)
+
+type BoolFeature struct {
+	key        string
+	defaultVal bool
+}
+
+func newFeature(suffix string, defaultVal bool) BoolFeature {
+	const envKeyRoot = "OTEL_GO_X_"
+	return BoolFeature{
+		key:        envKeyRoot + suffix,
+		defaultVal: defaultVal,
+	}
+}
+
+func (f BoolFeature) Enabled() bool {
+	v := os.Getenv(f.key)
+
+	val, err := strconv.ParseBool(v)
+	if err != nil {
+		return f.defaultVal
+	}
+
+	return val
+}
+`
+		featureDir := filepath.Join(tmpDir, "internal", "x")
+		if err := os.MkdirAll(featureDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(featureDir, "x.go"), []byte(feature), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := map[string][]string{
+			"OTEL_GO_X_VERBOSE":             {"false", "true"},
+			"OTEL_SEMCONV_STABILITY_OPT_IN": {"http/dup"},
+			"OTEL_TESTPKG_DEBUG":            {"1"},
+			"OTEL_TESTPKG_MODE":             {"fast", "safe"},
+			"OTEL_TESTPKG_TRACE":            {"1"},
+		}
+
+		if got := len(analysis.EnvVars); got != len(want) {
+			t.Fatalf("EnvVars count = %d, want %d: %+v", got, len(want), analysis.EnvVars)
+		}
+
+		for _, env := range analysis.EnvVars {
+			if !reflect.DeepEqual(env.Values, want[env.Name]) {
+				t.Errorf("EnvVar %s values = %v, want %v", env.Name, env.Values, want[env.Name])
+			}
+		}
+	})
+}
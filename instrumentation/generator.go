@@ -3,12 +3,21 @@ package instrumentation
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mikeblum/otel-explorer-go-docs/repo"
+	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v3"
 )
 
+// maxEnumMembers bounds the value set emitted as an enum attribute type.
+const maxEnumMembers = 20
+
+// instrumentationListFormat is the Ecosystem Explorer file format version.
+const instrumentationListFormat = 0.1
+
 func encodeYAMLFile(path string, data interface{}) error {
 	file, err := os.Create(path)
 	if err != nil {
@@ -59,9 +68,232 @@ func Generate(groups []Group) error {
 	return nil
 }
 
+// GenerateLibraries writes the per-library summary next to the registry. It is
+// kept outside registry/ since weaver parses every file in that directory.
+func GenerateLibraries(libraries []Library) error {
+	sort.Slice(libraries, func(i, j int) bool {
+		return libraries[i].Module < libraries[j].Module
+	})
+
+	librariesOutput := map[string]interface{}{
+		"libraries": libraries,
+	}
+	return encodeYAMLFile("libraries.yaml", librariesOutput)
+}
+
+// GenerateSemconvLag writes the libraries whose newest imported semconv
+// version is older than the pinned registry version.
+func GenerateSemconvLag(libraries []Library, pinned string) error {
+	lagOutput := map[string]interface{}{
+		"semconv_version": pinned,
+		"libraries":       semconvLag(libraries, pinned),
+	}
+	return encodeYAMLFile("semconv-lag.yaml", lagOutput)
+}
+
+func semconvLag(libraries []Library, pinned string) []SemconvLag {
+	lagging := []SemconvLag{}
+	for _, library := range libraries {
+		if len(library.SemconvVersions) == 0 {
+			continue
+		}
+		newest := library.SemconvVersions[len(library.SemconvVersions)-1]
+		if semver.Compare(newest, pinned) < 0 {
+			lagging = append(lagging, SemconvLag{
+				Name:     library.Name,
+				Module:   library.Module,
+				Versions: library.SemconvVersions,
+			})
+		}
+	}
+
+	sort.Slice(lagging, func(i, j int) bool {
+		return lagging[i].Module < lagging[j].Module
+	})
+
+	return lagging
+}
+
+// GenerateInstrumentationList writes the libraries in the Ecosystem Explorer
+// instrumentation list format so they can be loaded alongside the Java agent.
+func GenerateInstrumentationList(libraries []Library) error {
+	return encodeYAMLFile("instrumentation-list.yaml", newInstrumentationList(libraries))
+}
+
+func newInstrumentationList(libraries []Library) InstrumentationList {
+	list := InstrumentationList{
+		FileFormat: instrumentationListFormat,
+		Libraries:  make(map[string][]InstrumentationLibrary),
+	}
+
+	for _, library := range libraries {
+		key := sanitizePackageName(library.Module)
+		list.Libraries[key] = append(list.Libraries[key], newInstrumentationLibrary(library))
+	}
+	for _, entries := range list.Libraries {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].SourcePath < entries[j].SourcePath
+		})
+	}
+
+	return list
+}
+
+func newInstrumentationLibrary(library Library) InstrumentationLibrary {
+	entry := InstrumentationLibrary{
+		Name:             library.Name,
+		DisplayName:      library.DisplayName,
+		Description:      library.Description,
+		LibraryLink:      library.URL,
+		SourcePath:       library.Source,
+		MinimumGoVersion: library.GoVersion,
+		Scope:            InstrumentationScope{Name: library.Module},
+	}
+
+	// The scope named after the library wins over helper scopes.
+	for i, scope := range library.Scopes {
+		if i == 0 || scope.Name == library.ImportPath {
+			entry.Scope = InstrumentationScope{Name: scope.Name, SchemaURL: scope.SchemaURL}
+		}
+	}
+
+	if library.Target != nil {
+		target := library.Target.Path
+		if library.Target.Version != "" {
+			target += ":[" + library.Target.Version + ",)"
+		}
+		entry.TargetVersions = map[string][]string{"library": {target}}
+	}
+
+	for _, option := range library.Configuration {
+		config := InstrumentationConfig{
+			Name:        option.Name,
+			Description: option.Description,
+			Default:     option.Default,
+		}
+		if len(option.Parameters) == 1 {
+			config.Type = option.Parameters[0].Type
+		}
+		entry.Configurations = append(entry.Configurations, config)
+	}
+	for _, env := range library.Environment {
+		entry.Configurations = append(entry.Configurations, InstrumentationConfig{
+			Name: env.Name,
+			Type: "string",
+		})
+	}
+
+	for _, tel := range library.Telemetry {
+		telemetry := InstrumentationTelemetry{When: tel.When}
+		if telemetry.When == "" {
+			telemetry.When = "default"
+		}
+		telemetry.Spans = instrumentationSpans(tel.Spans)
+		for _, metric := range tel.Metrics {
+			telemetry.Metrics = append(telemetry.Metrics, InstrumentationMetric{
+				Name:        metric.Name,
+				Description: metric.Description,
+				Type:        instrumentationMetricType(metric),
+				Unit:        metric.Unit,
+				Attributes:  instrumentationAttributes(metric.Attributes),
+			})
+		}
+		sort.Slice(telemetry.Metrics, func(i, j int) bool {
+			return telemetry.Metrics[i].Name < telemetry.Metrics[j].Name
+		})
+		entry.Telemetry = append(entry.Telemetry, telemetry)
+	}
+
+	return entry
+}
+
+// instrumentationSpans lists spans by kind with the union of their
+// attributes, as the explorer does not name spans. Spans of unknown kind
+// are left out.
+func instrumentationSpans(spans []Span) []InstrumentationSpan {
+	attrsByKind := make(map[SpanKind][]Attribute)
+	var kinds []SpanKind
+	for _, span := range spans {
+		if span.Kind == "" {
+			continue
+		}
+		if _, ok := attrsByKind[span.Kind]; !ok {
+			kinds = append(kinds, span.Kind)
+		}
+		attrsByKind[span.Kind] = appendMissingAttributes(attrsByKind[span.Kind], span.Attributes...)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	var result []InstrumentationSpan
+	for _, kind := range kinds {
+		result = append(result, InstrumentationSpan{
+			SpanKind:   strings.ToUpper(string(kind)),
+			Attributes: instrumentationAttributes(attrsByKind[kind]),
+		})
+	}
+	return result
+}
+
+func instrumentationAttributes(attrs []Attribute) []InstrumentationAttribute {
+	var result []InstrumentationAttribute
+	for _, attr := range attrs {
+		result = append(result, InstrumentationAttribute{
+			Name: attr.Name,
+			Type: instrumentationAttributeType(attr.Type),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// instrumentationAttributeType maps attribute types to the Java SDK
+// AttributeType names the explorer uses, e.g. int[] -> LONG_ARRAY.
+func instrumentationAttributeType(attrType AttributeType) string {
+	if attrType == "" {
+		attrType = AttributeTypeString
+	}
+	name := strings.TrimSuffix(string(attrType), "[]")
+	switch name {
+	case "int":
+		name = "long"
+	case "string", "boolean", "double":
+	default:
+		name = "string"
+	}
+	name = strings.ToUpper(name)
+	if strings.HasSuffix(string(attrType), "[]") {
+		name += "_ARRAY"
+	}
+	return name
+}
+
+// instrumentationMetricType maps instruments to the Java SDK MetricDataType
+// names the explorer uses: sums and gauges carry the value type.
+func instrumentationMetricType(metric Metric) string {
+	valueType := "LONG"
+	if strings.HasPrefix(metric.Instrument, "Float64") {
+		valueType = "DOUBLE"
+	}
+	switch metric.Type {
+	case MetricTypeHistogram:
+		return "HISTOGRAM"
+	case MetricTypeGauge:
+		return valueType + "_GAUGE"
+	default:
+		return valueType + "_SUM"
+	}
+}
+
 func extractAttributeGroups(groups []Group) []AttributeDef {
 	attributeMap := make(map[string]AttributeDef)
+	values := make(map[string][]string)
+	enums := make(map[string]bool)
 
+	// An attribute is as stable as the least stable group using it.
 	for _, group := range groups {
 		for _, attrRef := range group.Attributes {
 			if _, ok := GetSemconvAttribute(attrRef.Ref); ok {
@@ -70,32 +302,118 @@ func extractAttributeGroups(groups []Group) []AttributeDef {
 
 			if _, exists := attributeMap[attrRef.Ref]; !exists {
 				brief := generateAttributeBrief(attrRef.Ref)
-				attrType := inferAttributeType(attrRef.Ref)
+				attrType := attrRef.Type
+				if attrType == "" {
+					attrType = inferAttributeType(attrRef.Ref)
+				}
 
 				attr := AttributeDef{
 					ID:        attrRef.Ref,
 					Type:      attrType,
 					Brief:     brief,
-					Stability: StabilityDevelopment,
-				}
-
-				if attrType == AttributeTypeString {
-					attr.Examples = []interface{}{attrRef.Ref}
+					Stability: groupStability(group),
 				}
 
 				attributeMap[attrRef.Ref] = attr
+				enums[attrRef.Ref] = true
+			} else if attr := attributeMap[attrRef.Ref]; attr.Stability != groupStability(group) {
+				attr.Stability = minStability(attr.Stability, groupStability(group))
+				attributeMap[attrRef.Ref] = attr
 			}
+
+			// An attribute is only an enum if every use of it is.
+			values[attrRef.Ref] = mergeStrings(values[attrRef.Ref], attrRef.Examples)
+			enums[attrRef.Ref] = enums[attrRef.Ref] && attrRef.Enum
 		}
 	}
 
 	var attrs []AttributeDef
-	for _, attr := range attributeMap {
+	for id, attr := range attributeMap {
+		attr.Examples = attributeExamples(values[id], attr.Type)
+		if enums[id] && attr.Type == AttributeTypeString {
+			attr.Members = enumMembers(values[id], attr.Stability)
+		}
 		attrs = append(attrs, attr)
 	}
 
 	return attrs
 }
 
+// groupStability returns a group's stability, defaulting to development.
+func groupStability(group Group) Stability {
+	if group.Stability == "" {
+		return StabilityDevelopment
+	}
+	return group.Stability
+}
+
+// enumMembers builds enum members for the constant values a string attribute
+// takes. Large value sets and values without a usable member ID stay plain
+// strings.
+func enumMembers(values []string, stability Stability) []EnumMember {
+	if len(values) > maxEnumMembers {
+		return nil
+	}
+
+	var members []EnumMember
+	ids := make(map[string]bool)
+	for _, value := range values {
+		id := sanitizeMetricName(sanitizeSpanName(value))
+		if id == "" || ids[id] {
+			return nil
+		}
+		ids[id] = true
+		members = append(members, EnumMember{
+			ID:        id,
+			Value:     value,
+			Stability: stability,
+		})
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Value < members[j].Value
+	})
+
+	return members
+}
+
+// attributeExamples converts example values to the attribute's type. Array
+// attributes get a single example holding every value.
+func attributeExamples(values []string, attrType AttributeType) []interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	elemType := AttributeType(strings.TrimSuffix(string(attrType), "[]"))
+	var examples []interface{}
+	for _, value := range values {
+		examples = append(examples, exampleValue(value, elemType))
+	}
+
+	if elemType != attrType {
+		return []interface{}{examples}
+	}
+	return examples
+}
+
+func exampleValue(value string, attrType AttributeType) interface{} {
+	switch attrType {
+	case AttributeTypeLong:
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case AttributeTypeDouble:
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case AttributeTypeBoolean:
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
 func inferAttributeType(attrName string) AttributeType {
 	if strings.Contains(attrName, "port") || strings.Contains(attrName, "status_code") {
 		return AttributeTypeLong
@@ -169,7 +487,7 @@ func generateAttributeBrief(attrName string) string {
 	return strings.Join(result, " ")
 }
 
-func Scan(repoName, repoPath string) ([]Group, error) {
+func Scan(repoName, repoPath string) ([]Group, []Library, error) {
 	var scanPaths []string
 
 	switch repoName {
@@ -181,7 +499,9 @@ func Scan(repoName, repoPath string) ([]Group, error) {
 		scanPaths = []string{filepath.Join(repoPath, "instrumentation")}
 	}
 
+	versions := moduleSetVersions(repoPath)
 	groupMap := make(map[string]*Group)
+	var libraries []Library
 	for _, scanPath := range scanPaths {
 		packages, err := Walk(scanPath)
 		if err != nil {
@@ -189,10 +509,13 @@ func Scan(repoName, repoPath string) ([]Group, error) {
 		}
 
 		for _, pkg := range packages {
-			pkgGroups, err := Parse(pkg.GoModPath, repoPath, repoName)
+			pkgGroups, library, err := Parse(pkg.GoModPath, repoPath, repoName, versions)
 			if err != nil {
 				continue
 			}
+			if library != nil {
+				libraries = append(libraries, *library)
+			}
 			for _, group := range pkgGroups {
 				if existing, ok := groupMap[group.ID]; ok {
 					attrMap := make(map[string]bool)
@@ -217,5 +540,5 @@ func Scan(repoName, repoPath string) ([]Group, error) {
 		groups = append(groups, *group)
 	}
 
-	return groups, nil
+	return groups, libraries, nil
 }
@@ -3,6 +3,7 @@ package instrumentation
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/mikeblum/otel-explorer-go-docs/repo"
@@ -49,6 +50,230 @@ func TestGenerate(t *testing.T) {
 		}
 	})
 
+	t.Run("generator - writes conditional requirement levels", func(t *testing.T) {
+		groups := []Group{
+			{
+				ID:   "testpkg.query.client.span",
+				Type: "span",
+				Attributes: []AttributeRef{
+					{Ref: "db.system.name", RequirementLevel: RequirementLevelRequired},
+					{Ref: "error.type", RequirementLevel: RequirementLevelConditionallyRequired, Condition: "err != nil"},
+				},
+			},
+		}
+
+		if err := Generate(groups); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		data, err := os.ReadFile("registry/signals.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var raw map[string][]map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		attrs := raw["groups"][0]["attributes"].([]interface{})
+		level := attrs[1].(map[string]interface{})["requirement_level"]
+		if !reflect.DeepEqual(level, map[string]interface{}{"conditionally_required": "err != nil"}) {
+			t.Errorf("requirement_level = %#v, want conditionally_required map", level)
+		}
+
+		var result map[string][]Group
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if !reflect.DeepEqual(result["groups"][0].Attributes, groups[0].Attributes) {
+			t.Errorf("Attributes = %+v, want %+v", result["groups"][0].Attributes, groups[0].Attributes)
+		}
+	})
+
+	t.Run("generator - writes semconv examples on attribute refs", func(t *testing.T) {
+		registryDir := t.TempDir()
+		registry := `groups:
+  - id: registry.http
+    type: attribute_group
+    attributes:
+      - id: http.route
+        type: string
+        examples: ["/users/:userID?", "{controller}/{action}/{id?}"]
+      - id: http.response.status_code
+        type: int
+        examples: [200]
+`
+		if err := os.WriteFile(filepath.Join(registryDir, "http.yaml"), []byte(registry), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := LoadSemconv(registryDir); err != nil {
+			t.Fatalf("LoadSemconv() error = %v", err)
+		}
+		t.Cleanup(func() {
+			_ = LoadSemconv("")
+		})
+
+		tmpDir := t.TempDir()
+		content := `package testpkg
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serve(ctx context.Context, tracer trace.Tracer, route string, status int) {
+	_, span := tracer.Start(ctx, "serve", trace.WithAttributes(attribute.String("http.route", route)))
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		goModContent := "module example.com/testpkg\n\ngo 1.24\n\nrequire go.opentelemetry.io/otel v1.38.0\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+		if err := Generate(analysis.Groups); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		data, err := os.ReadFile("registry/signals.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var raw map[string][]map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		examples := make(map[string]interface{})
+		for _, attr := range raw["groups"][0]["attributes"].([]interface{}) {
+			ref := attr.(map[string]interface{})
+			examples[ref["ref"].(string)] = ref["examples"]
+		}
+		want := map[string]interface{}{
+			"http.route":                []interface{}{"/users/:userID?", "{controller}/{action}/{id?}"},
+			"http.response.status_code": []interface{}{200},
+		}
+		if !reflect.DeepEqual(examples, want) {
+			t.Errorf("Ref examples = %#v, want %#v", examples, want)
+		}
+	})
+
+	t.Run("generator - writes resolved attribute types", func(t *testing.T) {
+		t.Cleanup(func() { os.Remove("registry/attributes.yaml") })
+
+		groups := []Group{
+			{
+				ID:        "testpkg.operation.server.span",
+				Type:      "span",
+				Stability: StabilityDevelopment,
+				Brief:     "Span operation for testpkg",
+				SpanKind:  SpanKindServer,
+				Attributes: []AttributeRef{
+					{Ref: "custom.retries", RequirementLevel: "recommended", Type: AttributeTypeLong, Examples: []string{"3"}},
+					{Ref: "custom.tags", RequirementLevel: "recommended", Type: AttributeTypeStringArray, Examples: []string{"a", "b"}},
+					{Ref: "custom.name", RequirementLevel: "recommended", Type: AttributeTypeString},
+				},
+			},
+		}
+
+		if err := Generate(groups); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		data, err := os.ReadFile("registry/attributes.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var result map[string][]AttributeGroup
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		want := map[string]AttributeType{
+			"custom.retries": AttributeTypeLong,
+			"custom.tags":    AttributeTypeStringArray,
+			"custom.name":    AttributeTypeString,
+		}
+		wantExamples := map[string][]interface{}{
+			"custom.retries": {3},
+			"custom.tags":    {[]interface{}{"a", "b"}},
+		}
+		for _, attr := range result["groups"][0].Attributes {
+			if attr.Type != want[attr.ID] {
+				t.Errorf("Attribute %s type = %q, want %q", attr.ID, attr.Type, want[attr.ID])
+			}
+			if !reflect.DeepEqual(attr.Examples, wantExamples[attr.ID]) {
+				t.Errorf("Attribute %s examples = %#v, want %#v", attr.ID, attr.Examples, wantExamples[attr.ID])
+			}
+		}
+	})
+
+	t.Run("generator - writes enum attribute types", func(t *testing.T) {
+		t.Cleanup(func() { os.Remove("registry/attributes.yaml") })
+
+		groups := []Group{
+			{
+				ID:   "testpkg.get.client.span",
+				Type: "span",
+				Attributes: []AttributeRef{
+					{Ref: "cache.operation", Type: AttributeTypeString, Examples: []string{"get"}, Enum: true},
+					{Ref: "cache.key", Type: AttributeTypeString, Examples: []string{"user"}, Enum: true},
+				},
+			},
+			{
+				ID:   "testpkg.put.client.span",
+				Type: "span",
+				Attributes: []AttributeRef{
+					{Ref: "cache.operation", Type: AttributeTypeString, Examples: []string{"put"}, Enum: true},
+					{Ref: "cache.key", Type: AttributeTypeString},
+				},
+			},
+		}
+
+		if err := Generate(groups); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		data, err := os.ReadFile("registry/attributes.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var result map[string][]AttributeGroup
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		for _, attr := range result["groups"][0].Attributes {
+			var values []string
+			for _, member := range attr.Members {
+				values = append(values, member.Value)
+			}
+			switch attr.ID {
+			case "cache.operation":
+				if !reflect.DeepEqual(values, []string{"get", "put"}) {
+					t.Errorf("cache.operation members = %v, want [get put]", values)
+				}
+			case "cache.key":
+				if len(values) != 0 || attr.Type != AttributeTypeString {
+					t.Errorf("cache.key members = %v type = %q, want plain string", values, attr.Type)
+				}
+			}
+		}
+	})
+
 	t.Run("generator - handles empty group list", func(t *testing.T) {
 		groups := []Group{}
 
@@ -72,6 +297,230 @@ func TestGenerate(t *testing.T) {
 	})
 }
 
+func TestAttributeMarshalYAML(t *testing.T) {
+	t.Run("Attribute.MarshalYAML - keeps string examples strings", func(t *testing.T) {
+		attrs := []Attribute{
+			{Name: "http.response.status_code", Type: AttributeTypeLong, Examples: []string{"200"}},
+			{Name: "http.request.method", Type: AttributeTypeString, Examples: []string{"GET", "200", "true"}},
+		}
+
+		data, err := yaml.Marshal(attrs)
+		if err != nil {
+			t.Fatalf("yaml.Marshal() error = %v", err)
+		}
+
+		var decoded []struct {
+			Name     string        `yaml:"name"`
+			Examples []interface{} `yaml:"examples"`
+		}
+		if err := yaml.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("yaml.Unmarshal() error = %v", err)
+		}
+
+		want := [][]interface{}{{200}, {"GET", "200", "true"}}
+		for i, attr := range decoded {
+			if !reflect.DeepEqual(attr.Examples, want[i]) {
+				t.Errorf("Attribute %s examples = %#v, want %#v", attr.Name, attr.Examples, want[i])
+			}
+		}
+	})
+}
+
+func TestGenerateLibraries(t *testing.T) {
+	t.Run("generator - writes library summaries", func(t *testing.T) {
+		t.Cleanup(func() { os.Remove("libraries.yaml") })
+
+		libraries := []Library{
+			{
+				Name:   "otelgin",
+				Module: "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin",
+				Spans: []SpanSummary{
+					{
+						Name:          "{spanName}",
+						Kind:          SpanKindServer,
+						Status:        []SpanStatus{{Code: StatusCodeError, Condition: "code >= 500"}},
+						RecordsErrors: true,
+					},
+				},
+			},
+		}
+
+		if err := GenerateLibraries(libraries); err != nil {
+			t.Fatalf("GenerateLibraries() error = %v", err)
+		}
+
+		data, err := os.ReadFile("libraries.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var result map[string][]Library
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		if got := len(result["libraries"]); got != 1 {
+			t.Fatalf("GenerateLibraries() wrote %d libraries, want 1", got)
+		}
+
+		span := result["libraries"][0].Spans[0]
+		if !span.RecordsErrors || span.Status[0].Condition != "code >= 500" {
+			t.Errorf("Generated span summary = %+v, want error semantics preserved", span)
+		}
+	})
+}
+
+func TestGenerateSemconvLag(t *testing.T) {
+	t.Run("generator - lists libraries behind the pinned semconv version", func(t *testing.T) {
+		t.Cleanup(func() { os.Remove("semconv-lag.yaml") })
+
+		libraries := []Library{
+			{Name: "otelhttp", Module: "example.com/otelhttp", SemconvVersions: []string{"v1.20.0", "v1.38.0"}},
+			{Name: "otelgrpc", Module: "example.com/otelgrpc", SemconvVersions: []string{"v1.26.0"}},
+			{Name: "otelaws", Module: "example.com/otelaws"},
+		}
+
+		if err := GenerateSemconvLag(libraries, "v1.38.0"); err != nil {
+			t.Fatalf("GenerateSemconvLag() error = %v", err)
+		}
+
+		data, err := os.ReadFile("semconv-lag.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var result struct {
+			Version   string       `yaml:"semconv_version"`
+			Libraries []SemconvLag `yaml:"libraries"`
+		}
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		if result.Version != "v1.38.0" {
+			t.Errorf("semconv_version = %q, want v1.38.0", result.Version)
+		}
+
+		if got := len(result.Libraries); got != 1 {
+			t.Fatalf("GenerateSemconvLag() wrote %d libraries, want 1: %+v", got, result.Libraries)
+		}
+
+		if got := result.Libraries[0].Name; got != "otelgrpc" {
+			t.Errorf("Lagging library = %q, want otelgrpc", got)
+		}
+	})
+}
+
+func TestGenerateInstrumentationList(t *testing.T) {
+	t.Run("generator - writes libraries in the Ecosystem Explorer format", func(t *testing.T) {
+		t.Cleanup(func() { os.Remove("instrumentation-list.yaml") })
+
+		libraries := []Library{
+			{
+				Name:        "otelgin",
+				DisplayName: "Gin",
+				Description: "Package otelgin instruments the gin-gonic/gin package.",
+				Module:      "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin",
+				ImportPath:  "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin",
+				URL:         "https://pkg.go.dev/go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin",
+				Source:      "instrumentation/github.com/gin-gonic/gin/otelgin",
+				Target:      &Dependency{Path: "github.com/gin-gonic/gin", Version: "v1.11.0"},
+				GoVersion:   "1.24.0",
+				Scopes: []Scope{
+					{Name: "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin", SchemaURL: "https://opentelemetry.io/schemas/1.37.0"},
+				},
+				Configuration: []ConfigOption{
+					{Name: "WithPropagators", Parameters: []ConfigParameter{{Name: "propagators", Type: "propagation.TextMapPropagator"}}},
+				},
+				Environment: []EnvVar{{Name: "OTEL_SEMCONV_STABILITY_OPT_IN"}},
+				Telemetry: []Telemetry{
+					{
+						Spans: []Span{
+							{Name: "{spanName}", Kind: SpanKindServer, Attributes: []Attribute{{Name: "http.route", Type: AttributeTypeString}}},
+							{Name: "gin.renderer.html", Kind: SpanKindServer, Attributes: []Attribute{{Name: "go.template", Type: AttributeTypeString}}},
+						},
+						Metrics: []Metric{
+							{Name: "http.server.request.duration", Type: MetricTypeHistogram, Instrument: "Float64Histogram", Unit: "s"},
+						},
+					},
+					{
+						When: "cfg.MetricAttributeFn != nil",
+						Metrics: []Metric{
+							{Name: "gin.requests", Type: MetricTypeCounter, Instrument: "Int64Counter", Attributes: []Attribute{{Name: "http.response.status_code", Type: AttributeTypeLong}}},
+						},
+					},
+				},
+			},
+		}
+
+		if err := GenerateInstrumentationList(libraries); err != nil {
+			t.Fatalf("GenerateInstrumentationList() error = %v", err)
+		}
+
+		data, err := os.ReadFile("instrumentation-list.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		var result InstrumentationList
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		want := InstrumentationList{
+			FileFormat: 0.1,
+			Libraries: map[string][]InstrumentationLibrary{
+				"gin": {
+					{
+						Name:             "otelgin",
+						DisplayName:      "Gin",
+						Description:      "Package otelgin instruments the gin-gonic/gin package.",
+						LibraryLink:      "https://pkg.go.dev/go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin",
+						SourcePath:       "instrumentation/github.com/gin-gonic/gin/otelgin",
+						MinimumGoVersion: "1.24.0",
+						Scope: InstrumentationScope{
+							Name:      "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin",
+							SchemaURL: "https://opentelemetry.io/schemas/1.37.0",
+						},
+						TargetVersions: map[string][]string{"library": {"github.com/gin-gonic/gin:[v1.11.0,)"}},
+						Configurations: []InstrumentationConfig{
+							{Name: "WithPropagators", Type: "propagation.TextMapPropagator"},
+							{Name: "OTEL_SEMCONV_STABILITY_OPT_IN", Type: "string"},
+						},
+						Telemetry: []InstrumentationTelemetry{
+							{
+								When: "default",
+								Metrics: []InstrumentationMetric{
+									{Name: "http.server.request.duration", Type: "HISTOGRAM", Unit: "s"},
+								},
+								Spans: []InstrumentationSpan{
+									{
+										SpanKind: "SERVER",
+										Attributes: []InstrumentationAttribute{
+											{Name: "go.template", Type: "STRING"},
+											{Name: "http.route", Type: "STRING"},
+										},
+									},
+								},
+							},
+							{
+								When: "cfg.MetricAttributeFn != nil",
+								Metrics: []InstrumentationMetric{
+									{Name: "gin.requests", Type: "LONG_SUM", Attributes: []InstrumentationAttribute{{Name: "http.response.status_code", Type: "LONG"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("GenerateInstrumentationList() wrote %+v, want %+v", result, want)
+		}
+	})
+}
+
 func TestScan(t *testing.T) {
 	t.Run("scanner - scans valid instrumentation directory", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -96,7 +545,7 @@ require (
 			t.Fatal(err)
 		}
 
-		groups, err := Scan(repo.RepoContrib, tmpDir)
+		groups, _, err := Scan(repo.RepoContrib, tmpDir)
 		if err != nil {
 			t.Fatalf("Scan() error = %v", err)
 		}
@@ -109,7 +558,7 @@ require (
 	t.Run("scanner - handles missing instrumentation directory", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
-		groups, err := Scan(repo.RepoContrib, tmpDir)
+		groups, _, err := Scan(repo.RepoContrib, tmpDir)
 		if err != nil {
 			t.Fatalf("Scan() error = %v", err)
 		}
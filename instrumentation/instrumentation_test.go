@@ -25,6 +25,21 @@ func getRepoPath(t *testing.T) string {
 	return filepath.Join(wd, ".repo/opentelemetry-go-contrib")
 }
 
+// loadSemconv loads the pinned semantic conventions registry for the test.
+func loadSemconv(t *testing.T) {
+	t.Helper()
+	semconvPath, err := repo.CheckoutSemconv()
+	if err != nil {
+		t.Fatalf("CheckoutSemconv() error = %v", err)
+	}
+	if err := LoadSemconv(semconvPath); err != nil {
+		t.Fatalf("LoadSemconv() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = LoadSemconv("")
+	})
+}
+
 func assertSpanHasAttribute(t *testing.T, attributes []Attribute, name string) {
 	t.Helper()
 	for _, attr := range attributes {
@@ -35,7 +50,30 @@ func assertSpanHasAttribute(t *testing.T, attributes []Attribute, name string) {
 	t.Errorf("Span missing required attribute %s", name)
 }
 
+func findSpan(t *testing.T, spans []Span, name string) Span {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("No span named %s, got spans: %+v", name, spans)
+	return Span{}
+}
+
+func findGroup(t *testing.T, groups []Group, id string) Group {
+	t.Helper()
+	for _, group := range groups {
+		if group.ID == id {
+			return group
+		}
+	}
+	t.Fatalf("No group %s, got groups: %+v", id, groups)
+	return Group{}
+}
+
 func TestAWSSDKInstrumentation(t *testing.T) {
+	loadSemconv(t)
 	repoPath := getRepoPath(t)
 	analysis, err := AnalyzePackage(filepath.Join(repoPath, "instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"))
 	if err != nil {
@@ -68,6 +106,7 @@ func TestAWSSDKInstrumentation(t *testing.T) {
 }
 
 func TestGinInstrumentation(t *testing.T) {
+	loadSemconv(t)
 	repoPath := getRepoPath(t)
 	analysis, err := AnalyzePackage(filepath.Join(repoPath, "instrumentation/github.com/gin-gonic/gin/otelgin"))
 	if err != nil {
@@ -80,11 +119,11 @@ func TestGinInstrumentation(t *testing.T) {
 
 	tel := analysis.Telemetry[0]
 
-	if got := len(tel.Spans); got != 1 {
-		t.Fatalf("Spans count = %d, want 1", got)
+	if got := len(tel.Spans); got != 2 {
+		t.Fatalf("Spans count = %d, want 2", got)
 	}
 
-	span := tel.Spans[0]
+	span := findSpan(t, tel.Spans, "{spanName}")
 	if span.Kind != SpanKindServer {
 		t.Errorf("Span kind = %v, want %s", span.Kind, SpanKindServer)
 	}
@@ -93,6 +132,9 @@ func TestGinInstrumentation(t *testing.T) {
 	assertSpanHasAttribute(t, span.Attributes, "http.response.status_code")
 	assertSpanHasAttribute(t, span.Attributes, "http.route")
 
+	htmlSpan := findSpan(t, tel.Spans, "gin.renderer.html")
+	assertSpanHasAttribute(t, htmlSpan.Attributes, "go.template")
+
 	if got := len(tel.Metrics); got != 3 {
 		t.Errorf("Metrics count = %d, want 3", got)
 	}
@@ -117,6 +159,7 @@ func TestGRPCInstrumentation(t *testing.T) {
 }
 
 func TestMongoInstrumentation(t *testing.T) {
+	loadSemconv(t)
 	repoPath := getRepoPath(t)
 	analysis, err := AnalyzePackage(filepath.Join(repoPath, "instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"))
 	if err != nil {
@@ -143,6 +186,7 @@ func TestMongoInstrumentation(t *testing.T) {
 }
 
 func TestRestfulInstrumentation(t *testing.T) {
+	loadSemconv(t)
 	repoPath := getRepoPath(t)
 	analysis, err := AnalyzePackage(filepath.Join(repoPath, "instrumentation/github.com/emicklei/go-restful/otelrestful"))
 	if err != nil {
@@ -193,7 +237,7 @@ func TestLambdaInstrumentation(t *testing.T) {
 func TestFullScanValidation(t *testing.T) {
 	t.Run("scan all instrumentation packages", func(t *testing.T) {
 		repoPath := getRepoPath(t)
-		groups, err := Scan(repo.RepoContrib, repoPath)
+		groups, _, err := Scan(repo.RepoContrib, repoPath)
 		if err != nil {
 			t.Fatalf("Scan() error = %v", err)
 		}
@@ -225,7 +269,7 @@ func TestFullScanValidation(t *testing.T) {
 
 	t.Run("validate no duplicate groups", func(t *testing.T) {
 		repoPath := getRepoPath(t)
-		groups, err := Scan(repo.RepoContrib, repoPath)
+		groups, _, err := Scan(repo.RepoContrib, repoPath)
 		if err != nil {
 			t.Fatalf("Scan() error = %v", err)
 		}
@@ -244,7 +288,7 @@ func TestFullScanValidation(t *testing.T) {
 
 	t.Run("validate groups have required fields", func(t *testing.T) {
 		repoPath := getRepoPath(t)
-		groups, err := Scan(repo.RepoContrib, repoPath)
+		groups, _, err := Scan(repo.RepoContrib, repoPath)
 		if err != nil {
 			t.Fatalf("Scan() error = %v", err)
 		}
@@ -256,7 +300,7 @@ func TestFullScanValidation(t *testing.T) {
 			if group.Type == "" {
 				t.Errorf("Group %s has empty type", group.ID)
 			}
-			if group.Type != "span" && group.Type != "metric" {
+			if group.Type != "span" && group.Type != "metric" && group.Type != "event" {
 				t.Errorf("Group %s has invalid type %s", group.ID, group.Type)
 			}
 		}
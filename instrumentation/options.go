@@ -0,0 +1,204 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// extractOptions documents the exported With* constructors returning one of
+// the package's *Option types, e.g. otelgin.WithTracerProvider.
+func extractOptions(pkg *packages.Package) []ConfigOption {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	defaults := extractConfigDefaults(pkg)
+
+	var options []ConfigOption
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || !isOptionConstructor(funcDecl, pkg) {
+				continue
+			}
+
+			fn, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			sig := fn.Type().(*types.Signature)
+
+			option := ConfigOption{
+				Name:       funcDecl.Name.Name,
+				Type:       types.TypeString(sig.Results().At(0).Type(), packageQualifier(pkg.Types)),
+				Parameters: optionParameters(sig, pkg.Types),
+			}
+			if funcDecl.Doc != nil {
+				option.Description = strings.TrimSpace(funcDecl.Doc.Text())
+			}
+
+			for _, field := range optionFields(funcDecl, pkg) {
+				if value, ok := defaults[field]; ok {
+					option.Default = value
+					break
+				}
+			}
+
+			options = append(options, option)
+		}
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Name < options[j].Name
+	})
+
+	return options
+}
+
+// isOptionConstructor reports whether funcDecl is an exported With* function
+// returning a named *Option type declared in pkg.
+func isOptionConstructor(funcDecl *ast.FuncDecl, pkg *packages.Package) bool {
+	name := funcDecl.Name.Name
+	if !strings.HasPrefix(name, "With") || !ast.IsExported(name) {
+		return false
+	}
+
+	fn, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Results().Len() != 1 {
+		return false
+	}
+
+	named, ok := sig.Results().At(0).Type().(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() == pkg.Types && obj.Exported() && strings.HasSuffix(obj.Name(), "Option")
+}
+
+func optionParameters(sig *types.Signature, pkg *types.Package) []ConfigParameter {
+	var params []ConfigParameter
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		paramType := types.TypeString(param.Type(), packageQualifier(pkg))
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			paramType = "..." + strings.TrimPrefix(paramType, "[]")
+		}
+		params = append(params, ConfigParameter{
+			Name: param.Name(),
+			Type: paramType,
+		})
+	}
+	return params
+}
+
+func packageQualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// optionFields returns the config struct fields an option constructor assigns.
+func optionFields(funcDecl *ast.FuncDecl, pkg *packages.Package) []types.Object {
+	var fields []types.Object
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			if field := structField(lhs, pkg); field != nil {
+				fields = append(fields, field)
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+func structField(expr ast.Expr, pkg *packages.Package) types.Object {
+	selExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	selection, ok := pkg.TypesInfo.Selections[selExpr]
+	if !ok || selection.Kind() != types.FieldVal {
+		return nil
+	}
+	return selection.Obj()
+}
+
+// extractConfigDefaults collects the default value of each config field from
+// struct literal initialisation, e.g. &config{Propagators: otel.GetTextMapPropagator()},
+// and from nil checks applied after the options, e.g.
+// if cfg.TracerProvider == nil { cfg.TracerProvider = otel.GetTracerProvider() }.
+func extractConfigDefaults(pkg *packages.Package) map[types.Object]string {
+	defaults := make(map[types.Object]string)
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && isOptionConstructor(funcDecl, pkg) {
+				continue
+			}
+
+			ast.Inspect(decl, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.CompositeLit:
+					for _, elt := range node.Elts {
+						kv, ok := elt.(*ast.KeyValueExpr)
+						if !ok {
+							continue
+						}
+						key, ok := kv.Key.(*ast.Ident)
+						if !ok {
+							continue
+						}
+						if field, ok := pkg.TypesInfo.Uses[key].(*types.Var); ok && field.IsField() {
+							defaults[field] = types.ExprString(kv.Value)
+						}
+					}
+				case *ast.IfStmt:
+					cond, ok := node.Cond.(*ast.BinaryExpr)
+					if !ok || cond.Op != token.EQL || !isNilIdent(cond.Y) {
+						return true
+					}
+					field := structField(cond.X, pkg)
+					if field == nil {
+						return true
+					}
+					for _, stmt := range node.Body.List {
+						assign, ok := stmt.(*ast.AssignStmt)
+						if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+							continue
+						}
+						if structField(assign.Lhs[0], pkg) == field {
+							defaults[field] = types.ExprString(assign.Rhs[0])
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	return defaults
+}
+
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
@@ -0,0 +1,133 @@
+package instrumentation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractOptions(t *testing.T) {
+	t.Run("extractOptions - documents With* option constructors", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type config struct {
+	TracerProvider trace.TracerProvider
+	Propagators    propagation.TextMapPropagator
+	Filters        []Filter
+}
+
+// Filter is a predicate used to determine whether a request should be traced.
+type Filter func(*http.Request) bool
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+func newConfig(opts ...Option) *config {
+	c := &config{
+		Propagators: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	if c.TracerProvider == nil {
+		c.TracerProvider = otel.GetTracerProvider()
+	}
+	return c
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.TracerProvider = provider
+		}
+	})
+}
+
+// WithPropagators specifies propagators to use for extracting information.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.Propagators = propagators
+	})
+}
+
+// WithFilter adds a filter to the list of filters used by the handler.
+func WithFilter(f ...Filter) Option {
+	return optionFunc(func(c *config) {
+		c.Filters = append(c.Filters, f...)
+	})
+}
+
+// WithName is not an option constructor.
+func WithName(name string) string {
+	return name
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := []ConfigOption{
+			{
+				Name:        "WithFilter",
+				Type:        "Option",
+				Description: "WithFilter adds a filter to the list of filters used by the handler.",
+				Parameters:  []ConfigParameter{{Name: "f", Type: "...Filter"}},
+			},
+			{
+				Name:        "WithPropagators",
+				Type:        "Option",
+				Description: "WithPropagators specifies propagators to use for extracting information.",
+				Parameters:  []ConfigParameter{{Name: "propagators", Type: "propagation.TextMapPropagator"}},
+				Default:     "otel.GetTextMapPropagator()",
+			},
+			{
+				Name:        "WithTracerProvider",
+				Type:        "Option",
+				Description: "WithTracerProvider specifies a tracer provider to use for creating a tracer.\nIf none is specified, the global provider is used.",
+				Parameters:  []ConfigParameter{{Name: "provider", Type: "trace.TracerProvider"}},
+				Default:     "otel.GetTracerProvider()",
+			},
+		}
+
+		if !reflect.DeepEqual(analysis.Options, want) {
+			t.Errorf("Options = %+v, want %+v", analysis.Options, want)
+		}
+	})
+}
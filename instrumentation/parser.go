@@ -1,10 +1,16 @@
 package instrumentation
 
 import (
+	"go/doc"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 const (
@@ -36,27 +42,160 @@ var displayNameMap = map[string]string{
 	"runtime":    "Runtime",
 }
 
-func Parse(goModPath string, repoRoot string, repoName string) ([]Group, error) {
+// Parse analyzes the module at goModPath. versions maps module paths to the
+// version of their module set, see moduleSetVersions.
+func Parse(goModPath string, repoRoot string, repoName string, versions map[string]string) ([]Group, *Library, error) {
 	data, err := os.ReadFile(goModPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	_, err = modfile.Parse(goModPath, data, nil)
+	modFile, err := modfile.Parse(goModPath, data, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	pkgPath := filepath.Dir(goModPath)
 
 	analysis, err := AnalyzePackage(pkgPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if analysis == nil || len(analysis.Groups) == 0 {
-		return nil, nil
+	// Libraries emitting only semconv-defined telemetry have no groups of
+	// their own but are still listed.
+	if analysis == nil || (len(analysis.Groups) == 0 && len(analysis.Telemetry) == 0) {
+		return nil, nil, nil
 	}
 
-	return analysis.Groups, nil
+	library := newLibrary(analysis, modFile)
+	if source, err := filepath.Rel(repoRoot, pkgPath); err == nil {
+		library.Source = filepath.ToSlash(source)
+	}
+	if version := versions[library.Module]; version != "" {
+		library.Version = version
+	}
+	library.Stability = moduleStability(library.Module, library.Version)
+	applyStability(analysis.Groups, library.Stability, library.Deprecated)
+
+	return analysis.Groups, &library, nil
+}
+
+func newLibrary(analysis *PackageAnalysis, modFile *modfile.File) Library {
+	library := Library{
+		Name:            analysis.Name,
+		DisplayName:     displayName(analysis.ImportPath),
+		Description:     new(doc.Package).Synopsis(analysis.Description),
+		ImportPath:      analysis.ImportPath,
+		Version:         scopeVersion(analysis.Scopes),
+		SemconvVersions: analysis.SemconvVersions,
+		Scopes:          analysis.Scopes,
+		Propagation:     analysis.Propagation,
+		Configuration:   analysis.Options,
+		Environment:     analysis.EnvVars,
+		Telemetry:       analysis.Telemetry,
+	}
+	if modFile.Module != nil {
+		library.Module = modFile.Module.Mod.Path
+		library.Target = targetLibrary(library.Module, modFile.Require)
+	}
+	if notice := deprecationNotice(analysis.Description); notice != "" {
+		library.Deprecated = &Deprecation{Reason: DeprecationUncategorized, Note: notice}
+	}
+	if analysis.ImportPath != "" {
+		library.URL = (&url.URL{Scheme: httpsScheme, Host: pkgGoDevHost, Path: "/" + analysis.ImportPath}).String()
+	}
+	if modFile.Go != nil {
+		library.GoVersion = modFile.Go.Version
+	}
+	for _, req := range modFile.Require {
+		if !req.Indirect && (req.Mod.Path == otelPrefix+"/otel" || strings.HasPrefix(req.Mod.Path, otelPrefix+"/otel/")) {
+			library.OTel = append(library.OTel, Dependency{Path: req.Mod.Path, Version: req.Mod.Version})
+		}
+	}
+
+	for _, tel := range analysis.Telemetry {
+		for _, span := range tel.Spans {
+			library.Spans = append(library.Spans, SpanSummary{
+				Name:          span.Name,
+				Kind:          span.Kind,
+				Status:        span.Status,
+				RecordsErrors: recordsErrors(span),
+			})
+		}
+		for _, metric := range tel.Metrics {
+			if metric.SemconvRef != "" {
+				ref := SemconvRef{
+					Name: metric.Name,
+					Ref:  metric.SemconvRef,
+				}
+				if semconvMetric, ok := GetSemconvMetric(metric.Name); ok {
+					ref.Stability = semconvMetric.Stability
+					ref.Deprecated = semconvMetric.Deprecated
+				}
+				library.SemconvMetrics = append(library.SemconvMetrics, ref)
+			}
+		}
+	}
+	sort.Slice(library.SemconvMetrics, func(i, j int) bool {
+		return library.SemconvMetrics[i].Name < library.SemconvMetrics[j].Name
+	})
+
+	for _, group := range analysis.Groups {
+		library.Groups = append(library.Groups, group.ID)
+	}
+	sort.Strings(library.Groups)
+
+	return library
+}
+
+// targetLibrary returns the library an instrumentation module instruments:
+// the requirement its module path embeds, e.g. github.com/gin-gonic/gin for
+// .../instrumentation/github.com/gin-gonic/gin/otelgin, or the standard
+// library package for modules such as .../instrumentation/net/http/otelhttp.
+func targetLibrary(modulePath string, requires []*modfile.Require) *Dependency {
+	var target *Dependency
+	var targetPrefix string
+	for _, req := range requires {
+		// Module paths embed the target without its major version suffix,
+		// e.g. github.com/labstack/echo for github.com/labstack/echo/v4.
+		prefix, _, ok := module.SplitPathVersion(req.Mod.Path)
+		if !ok {
+			prefix = req.Mod.Path
+		}
+		if req.Indirect || testDependencies[req.Mod.Path] || !strings.Contains(modulePath+"/", "/"+prefix+"/") {
+			continue
+		}
+		if target == nil || len(prefix) > len(targetPrefix) {
+			target = &Dependency{Path: req.Mod.Path, Version: req.Mod.Version}
+			targetPrefix = prefix
+		}
+	}
+	if target != nil {
+		return target
+	}
+
+	_, rest, ok := strings.Cut(modulePath, "/instrumentation/")
+	if !ok {
+		return nil
+	}
+	pkgPath := path.Dir(rest)
+	// Standard library import paths have no dot in their first element.
+	if pkgPath == "." || strings.Contains(strings.Split(pkgPath, "/")[0], ".") {
+		return nil
+	}
+	return &Dependency{Path: pkgPath}
+}
+
+// displayName returns the display name of the library at importPath, e.g.
+// MongoDB for .../otelmongo.
+func displayName(importPath string) string {
+	if importPath == "" {
+		return ""
+	}
+	name := sanitizePackageName(importPath)
+	if displayName, ok := displayNameMap[name]; ok {
+		return displayName
+	}
+	return name
 }
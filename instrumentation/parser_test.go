@@ -3,9 +3,11 @@ package instrumentation
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/mikeblum/otel-explorer-go-docs/repo"
+	"golang.org/x/mod/modfile"
 )
 
 func TestParse(t *testing.T) {
@@ -33,7 +35,7 @@ require (
 			t.Fatal(err)
 		}
 
-		groups, err := Parse(goModPath, tmpDir, repo.RepoContrib)
+		groups, _, err := Parse(goModPath, tmpDir, repo.RepoContrib, nil)
 		if err != nil {
 			t.Fatalf("Parse() error = %v", err)
 		}
@@ -43,4 +45,391 @@ require (
 		}
 	})
 
+	t.Run("parser - lists semconv metrics of libraries without groups", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/semconv/v1.37.0/goconv"
+)
+
+func newMetrics(meter metric.Meter) error {
+	_, err := goconv.NewGoroutineCount(meter)
+	return err
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		groups, library, err := Parse(goModPath, tmpDir, repo.RepoContrib, nil)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if len(groups) != 0 {
+			t.Errorf("Parse() groups = %+v, want none", groups)
+		}
+		if library == nil {
+			t.Fatal("Parse() library = nil, want library")
+		}
+
+		want := []SemconvRef{{Name: "go.goroutine.count", Ref: "metric.go.goroutine.count"}}
+		if !reflect.DeepEqual(library.SemconvMetrics, want) {
+			t.Errorf("SemconvMetrics = %+v, want %+v", library.SemconvMetrics, want)
+		}
+	})
+
+	t.Run("parser - records library metadata", func(t *testing.T) {
+		repoRoot := t.TempDir()
+		moduleDir := filepath.Join(repoRoot, "instrumentation", "example.com", "mongo", "otelmongo")
+		if err := os.MkdirAll(moduleDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		content := `// Package otelmongo instruments the example MongoDB client. It records a
+// span per command.
+package otelmongo
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func find(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "find", trace.WithAttributes(attribute.String("mongo.filter", "{}")))
+	defer span.End()
+}
+`
+		if err := os.WriteFile(filepath.Join(moduleDir, "mongo.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModPath := filepath.Join(moduleDir, "go.mod")
+		goModContent := `module example.com/instrumentation/example.com/mongo/otelmongo
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, library, err := Parse(goModPath, repoRoot, repo.RepoContrib, nil)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if library == nil {
+			t.Fatal("Parse() library = nil, want library")
+		}
+
+		importPath := "example.com/instrumentation/example.com/mongo/otelmongo"
+		if library.ImportPath != importPath {
+			t.Errorf("ImportPath = %v, want %v", library.ImportPath, importPath)
+		}
+		if library.DisplayName != "MongoDB" {
+			t.Errorf("DisplayName = %v, want MongoDB", library.DisplayName)
+		}
+		if want := "Package otelmongo instruments the example MongoDB client."; library.Description != want {
+			t.Errorf("Description = %v, want %v", library.Description, want)
+		}
+		if want := "https://pkg.go.dev/" + importPath; library.URL != want {
+			t.Errorf("URL = %v, want %v", library.URL, want)
+		}
+		if want := "instrumentation/example.com/mongo/otelmongo"; library.Source != want {
+			t.Errorf("Source = %v, want %v", library.Source, want)
+		}
+		if want := []string{"mongo.find.internal.span"}; !reflect.DeepEqual(library.Groups, want) {
+			t.Errorf("Groups = %v, want %v", library.Groups, want)
+		}
+	})
+
+	t.Run("parser - infers stability from module sets, semconv and deprecation notices", func(t *testing.T) {
+		registryDir := t.TempDir()
+		registry := `groups:
+  - id: registry.db
+    type: attribute_group
+    attributes:
+      - id: db.system.name
+        type: string
+        stability: release_candidate
+      - id: db.operation.name
+        type: string
+        stability: stable
+      - id: db.system
+        type: string
+        stability: development
+        deprecated:
+          reason: renamed
+          renamed_to: db.system.name
+  - id: metric.db.client.operation.duration
+    type: metric
+    metric_name: db.client.operation.duration
+    stability: release_candidate
+    deprecated:
+      reason: renamed
+      renamed_to: db.client.duration
+`
+		if err := os.WriteFile(filepath.Join(registryDir, "db.yaml"), []byte(registry), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := LoadSemconv(registryDir); err != nil {
+			t.Fatalf("LoadSemconv() error = %v", err)
+		}
+		t.Cleanup(func() {
+			_ = LoadSemconv("")
+		})
+
+		repoRoot := t.TempDir()
+		versions := `module-sets:
+  stable-v1:
+    version: v1.2.0
+    modules:
+      - example.com/instrumentation/otelsql
+  experimental:
+    version: v0.4.0
+    modules:
+      - example.com/instrumentation/otelsqlx
+`
+		if err := os.WriteFile(filepath.Join(repoRoot, "versions.yaml"), []byte(versions), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		stable := `// Package otelsql instruments database/sql.
+package otelsql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func query(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "query", trace.WithAttributes(
+		attribute.String("db.operation.name", "SELECT"),
+		attribute.Bool("sql.prepared", true),
+	))
+	defer span.End()
+}
+
+func ping(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "ping", trace.WithAttributes(
+		attribute.String("db.system.name", "postgresql"),
+		attribute.Bool("sql.prepared", false),
+	))
+	defer span.End()
+}
+
+func exec(ctx context.Context, tracer trace.Tracer) {
+	_, span := tracer.Start(ctx, "exec", trace.WithAttributes(
+		attribute.String("db.system.name", "postgresql"),
+		attribute.String("db.system", "postgresql"),
+	))
+	defer span.End()
+}
+`
+		deprecated := `// Package otelsqlx instruments sqlx.
+//
+// Deprecated: otelsqlx is no longer maintained,
+// use otelsql instead.
+package otelsqlx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func query(ctx context.Context, tracer trace.Tracer, meter metric.Meter) {
+	_, span := tracer.Start(ctx, "query")
+	defer span.End()
+
+	duration, _ := meter.Float64Histogram("db.client.operation.duration")
+	duration.Record(ctx, 1)
+}
+`
+		parse := func(name, content string) ([]Group, *Library) {
+			moduleDir := filepath.Join(repoRoot, name)
+			if err := os.MkdirAll(moduleDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(moduleDir, name+".go"), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			goModPath := filepath.Join(moduleDir, "go.mod")
+			goModContent := "module example.com/instrumentation/" + name + "\n\ngo 1.24\n\nrequire go.opentelemetry.io/otel v1.38.0\n"
+			if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			groups, library, err := Parse(goModPath, repoRoot, repo.RepoContrib, moduleSetVersions(repoRoot))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if library == nil {
+				t.Fatal("Parse() library = nil, want library")
+			}
+			return groups, library
+		}
+
+		groups, library := parse("otelsql", stable)
+		if library.Version != "v1.2.0" || library.Stability != StabilityStable || library.Deprecated != nil {
+			t.Errorf("library = %v %v %+v, want v1.2.0 stable and not deprecated", library.Version, library.Stability, library.Deprecated)
+		}
+		stability := make(map[string]Stability)
+		for _, group := range groups {
+			stability[group.ID] = group.Stability
+		}
+		wantStability := map[string]Stability{
+			"sql.query.internal.span": StabilityStable,
+			"sql.ping.internal.span":  StabilityDevelopment,
+			"sql.exec.internal.span":  StabilityDevelopment,
+		}
+		if !reflect.DeepEqual(stability, wantStability) {
+			t.Errorf("group stability = %v, want %v", stability, wantStability)
+		}
+		for _, group := range groups {
+			if group.Deprecated != nil {
+				t.Errorf("group %s Deprecated = %+v, want nil", group.ID, group.Deprecated)
+			}
+			var want string
+			if group.ID == "sql.exec.internal.span" {
+				want = "Records deprecated semantic convention attributes `db.system`."
+			}
+			if group.Note != want {
+				t.Errorf("group %s Note = %q, want %q", group.ID, group.Note, want)
+			}
+		}
+		for _, attr := range extractAttributeGroups(groups) {
+			if attr.ID == "sql.prepared" && attr.Stability != StabilityDevelopment {
+				t.Errorf("sql.prepared stability = %v, want %v", attr.Stability, StabilityDevelopment)
+			}
+		}
+
+		groups, library = parse("otelsqlx", deprecated)
+		wantDeprecation := &Deprecation{Reason: DeprecationUncategorized, Note: "otelsqlx is no longer maintained, use otelsql instead."}
+		if library.Stability != StabilityDevelopment || !reflect.DeepEqual(library.Deprecated, wantDeprecation) {
+			t.Errorf("library = %v %+v, want development and deprecated %+v", library.Stability, library.Deprecated, wantDeprecation)
+		}
+		for _, group := range groups {
+			if !reflect.DeepEqual(group.Deprecated, wantDeprecation) {
+				t.Errorf("group %s Deprecated = %+v, want %+v", group.ID, group.Deprecated, wantDeprecation)
+			}
+		}
+		wantMetrics := []SemconvRef{{
+			Name:       "db.client.operation.duration",
+			Ref:        "metric.db.client.operation.duration",
+			Stability:  "release_candidate",
+			Deprecated: &Deprecation{Reason: "renamed", RenamedTo: "db.client.duration"},
+		}}
+		if !reflect.DeepEqual(library.SemconvMetrics, wantMetrics) {
+			t.Errorf("SemconvMetrics = %+v, want %+v", library.SemconvMetrics, wantMetrics)
+		}
+	})
+}
+
+func TestNewLibrary(t *testing.T) {
+	tests := []struct {
+		name       string
+		goMod      string
+		wantTarget *Dependency
+		wantGo     string
+		wantOTel   []Dependency
+	}{
+		{
+			name: "newLibrary - third-party target library",
+			goMod: `module go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo
+
+go 1.24.0
+
+require (
+	github.com/stretchr/testify v1.11.1
+	go.mongodb.org/mongo-driver v1.17.4
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+)
+
+require go.opentelemetry.io/otel/metric v1.38.0 // indirect
+`,
+			wantTarget: &Dependency{Path: "go.mongodb.org/mongo-driver", Version: "v1.17.4"},
+			wantGo:     "1.24.0",
+			wantOTel: []Dependency{
+				{Path: "go.opentelemetry.io/otel", Version: "v1.38.0"},
+				{Path: "go.opentelemetry.io/otel/trace", Version: "v1.38.0"},
+			},
+		},
+		{
+			name: "newLibrary - target library with a major version suffix",
+			goMod: `module go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho
+
+go 1.24.0
+
+require (
+	github.com/labstack/echo/v4 v4.13.4
+	go.opentelemetry.io/otel v1.38.0
+)
+`,
+			wantTarget: &Dependency{Path: "github.com/labstack/echo/v4", Version: "v4.13.4"},
+			wantGo:     "1.24.0",
+			wantOTel:   []Dependency{{Path: "go.opentelemetry.io/otel", Version: "v1.38.0"}},
+		},
+		{
+			name: "newLibrary - standard library target",
+			goMod: `module go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp
+
+go 1.23.0
+
+require go.opentelemetry.io/otel v1.38.0
+`,
+			wantTarget: &Dependency{Path: "net/http"},
+			wantGo:     "1.23.0",
+			wantOTel:   []Dependency{{Path: "go.opentelemetry.io/otel", Version: "v1.38.0"}},
+		},
+		{
+			name: "newLibrary - no target library",
+			goMod: `module go.opentelemetry.io/contrib/instrumentation/runtime
+
+go 1.23.0
+`,
+			wantGo: "1.23.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modFile, err := modfile.Parse("go.mod", []byte(tt.goMod), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			library := newLibrary(&PackageAnalysis{Name: "test"}, modFile)
+
+			if !reflect.DeepEqual(library.Target, tt.wantTarget) {
+				t.Errorf("Target = %+v, want %+v", library.Target, tt.wantTarget)
+			}
+			if library.GoVersion != tt.wantGo {
+				t.Errorf("GoVersion = %v, want %v", library.GoVersion, tt.wantGo)
+			}
+			if !reflect.DeepEqual(library.OTel, tt.wantOTel) {
+				t.Errorf("OTel = %+v, want %+v", library.OTel, tt.wantOTel)
+			}
+		})
+	}
 }
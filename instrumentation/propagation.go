@@ -0,0 +1,154 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const propagationPkgPath = "go.opentelemetry.io/otel/propagation"
+
+// maxCarrierDepth bounds how deep carrier struct fields are searched.
+const maxCarrierDepth = 3
+
+// carrierKinds maps well-known carrier types to the transport they carry
+// context over.
+var carrierKinds = map[string]string{
+	propagationPkgPath + ".HeaderCarrier":                                  "http_headers",
+	propagationPkgPath + ".MapCarrier":                                     "map",
+	"net/http.Header":                                                      "http_headers",
+	"google.golang.org/grpc/metadata.MD":                                   "grpc_metadata",
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types.MessageAttributeValue": "sqs_message_attributes",
+}
+
+// extractPropagation documents how the module propagates context: the
+// carriers passed to TextMapPropagator.Inject and Extract, whether the global
+// otel.GetTextMapPropagator() is used and whether WithPropagators overrides it.
+func extractPropagation(pkgs []*packages.Package, resolver *attrResolver, options []ConfigOption) *Propagation {
+	propagation := &Propagation{}
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				callExpr, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				if fn := calledFuncExpr(callExpr.Fun, pkg); fn != nil && fn.FullName() == otelPkgPath+".GetTextMapPropagator" {
+					propagation.DefaultPropagator = "otel.GetTextMapPropagator()"
+					return true
+				}
+
+				selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+				if !ok || len(callExpr.Args) != 2 {
+					return true
+				}
+				if selExpr.Sel.Name != "Inject" && selExpr.Sel.Name != "Extract" {
+					return true
+				}
+				if !receiverImplements(selExpr, pkg, propagationPkgPath, "TextMapPropagator") {
+					return true
+				}
+
+				// Carriers passed as interfaces are followed to their values.
+				var carriers []Carrier
+				if carrier, ok := carrierOf(callExpr.Args[1], pkg); ok {
+					carriers = append(carriers, carrier)
+				} else {
+					for _, leaf := range resolver.expand(callExpr.Args[1], pkg) {
+						if carrier, ok := carrierOf(leaf.expr, leaf.pkg); ok {
+							carriers = append(carriers, carrier)
+						}
+					}
+				}
+
+				for _, carrier := range carriers {
+					carrier.Source = callSite(callExpr, pkg)
+					if selExpr.Sel.Name == "Inject" {
+						propagation.Inject = mergeCarriers(propagation.Inject, carrier)
+					} else {
+						propagation.Extract = mergeCarriers(propagation.Extract, carrier)
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	for _, option := range options {
+		if option.Name == "WithPropagators" {
+			propagation.Option = option.Name
+		}
+	}
+
+	if len(propagation.Inject) == 0 && len(propagation.Extract) == 0 && propagation.DefaultPropagator == "" {
+		return nil
+	}
+
+	sort.Slice(propagation.Inject, func(i, j int) bool {
+		return propagation.Inject[i].Type < propagation.Inject[j].Type
+	})
+	sort.Slice(propagation.Extract, func(i, j int) bool {
+		return propagation.Extract[i].Type < propagation.Extract[j].Type
+	})
+
+	return propagation
+}
+
+// carrierOf describes the concrete carrier an expression evaluates to.
+func carrierOf(expr ast.Expr, pkg *packages.Package) (Carrier, bool) {
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Type == nil || types.IsInterface(tv.Type) {
+		return Carrier{}, false
+	}
+	return Carrier{
+		Type: types.TypeString(tv.Type, packageQualifier(pkg.Types)),
+		Kind: carrierKind(tv.Type, 0),
+	}, true
+}
+
+// carrierKind classifies a carrier by the well-known type it is, or wraps
+// as a field or map value, e.g. a struct holding a metadata.MD.
+func carrierKind(t types.Type, depth int) string {
+	if depth > maxCarrierDepth {
+		return ""
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		if kind, ok := carrierKinds[named.Obj().Pkg().Path()+"."+named.Obj().Name()]; ok {
+			return kind
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if kind := carrierKind(u.Field(i).Type(), depth+1); kind != "" {
+				return kind
+			}
+		}
+	case *types.Map:
+		return carrierKind(u.Elem(), depth+1)
+	}
+
+	return ""
+}
+
+func mergeCarriers(existing []Carrier, carrier Carrier) []Carrier {
+	for _, e := range existing {
+		if e.Type == carrier.Type {
+			return existing
+		}
+	}
+	return append(existing, carrier)
+}
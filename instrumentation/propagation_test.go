@@ -0,0 +1,120 @@
+package instrumentation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractPropagation(t *testing.T) {
+	t.Run("extractPropagation - records carriers, default propagator and option", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type config struct {
+	Propagators propagation.TextMapPropagator
+}
+
+// Option specifies instrumentation configuration options.
+type Option func(*config)
+
+// WithPropagators configures the propagators used to extract and inject context.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.Propagators = propagators
+	}
+}
+
+func newConfig() config {
+	return config{Propagators: otel.GetTextMapPropagator()}
+}
+
+type headerSupplier struct {
+	header http.Header
+}
+
+func (s *headerSupplier) Get(key string) string { return s.header.Get(key) }
+func (s *headerSupplier) Set(key, value string) { s.header.Set(key, value) }
+func (s *headerSupplier) Keys() []string        { return nil }
+
+func handle(cfg config, r *http.Request) context.Context {
+	return cfg.Propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+}
+
+func send(ctx context.Context, cfg config, r *http.Request) {
+	carrier := &headerSupplier{header: r.Header}
+	cfg.Propagators.Inject(ctx, carrier)
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := &Propagation{
+			Extract:           []Carrier{{Type: "propagation.HeaderCarrier", Kind: "http_headers", Source: "test.go:38"}},
+			Inject:            []Carrier{{Type: "*headerSupplier", Kind: "http_headers", Source: "test.go:43"}},
+			DefaultPropagator: "otel.GetTextMapPropagator()",
+			Option:            "WithPropagators",
+		}
+
+		if !reflect.DeepEqual(analysis.Propagation, want) {
+			t.Errorf("Propagation = %+v, want %+v", analysis.Propagation, want)
+		}
+	})
+
+	t.Run("extractPropagation - omits packages without propagation", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+func noop() {}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		if analysis.Propagation != nil {
+			t.Errorf("Propagation = %+v, want nil", analysis.Propagation)
+		}
+	})
+}
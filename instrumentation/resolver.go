@@ -0,0 +1,607 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// attrResolver follows attribute values through local variables, slice and
+// map literals, append chains and helper function return values declared
+// anywhere in the analyzed module, e.g. internal/semconv subpackages.
+type attrResolver struct {
+	pkgs   map[string]*packages.Package
+	funcs  map[string]funcSource
+	memo   map[interface{}][]exprLeaf
+	active map[interface{}]bool
+
+	// Lazily built indexes used to resolve string values flowing through
+	// function parameters and struct fields.
+	calls  map[string][]funcCall
+	fields map[types.Object][]exprLeaf
+
+	// options maps config fields to the With* option assigning them.
+	options map[types.Object]string
+
+	// convFiles caches parsed semconv helper source files.
+	convFiles map[string]*ast.File
+}
+
+// funcCall is a call expression and the package it appears in.
+type funcCall struct {
+	call *ast.CallExpr
+	pkg  *packages.Package
+}
+
+// funcSource is a function declaration and the package it was loaded from.
+type funcSource struct {
+	decl *ast.FuncDecl
+	pkg  *packages.Package
+}
+
+// exprLeaf is an expression that cannot be followed any further, together
+// with the package whose type information describes it.
+type exprLeaf struct {
+	expr ast.Expr
+	pkg  *packages.Package
+}
+
+// funcResult identifies one result (or all results, when index is -1) of a
+// function declaration.
+type funcResult struct {
+	decl  *ast.FuncDecl
+	index int
+}
+
+func newAttrResolver(pkgs []*packages.Package) *attrResolver {
+	r := &attrResolver{
+		pkgs:   make(map[string]*packages.Package),
+		funcs:  make(map[string]funcSource),
+		memo:   make(map[interface{}][]exprLeaf),
+		active: make(map[interface{}]bool),
+	}
+
+	for _, pkg := range pkgs {
+		r.pkgs[pkg.PkgPath] = pkg
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+				if fn, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func); ok {
+					r.funcs[fn.FullName()] = funcSource{decl: funcDecl, pkg: pkg}
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// attributes resolves every attribute reachable from expr.
+func (r *attrResolver) attributes(expr ast.Expr, pkg *packages.Package) []Attribute {
+	var attributes []Attribute
+
+	for _, leaf := range r.expand(expr, pkg) {
+		if attr := parseAttributeExpr(leaf.expr, leaf.pkg); attr.Name != "" {
+			attr.When = r.guard(leaf.expr, leaf.pkg)
+			attr.Condition = enclosingCondition(leaf.expr, leaf.pkg)
+			attr.Examples, attr.Enum = r.examples(leaf.expr, leaf.pkg)
+			if semconvAttr, ok := GetSemconvAttribute(attr.Name); ok && len(attr.Examples) == 0 {
+				attr.Examples = semconvAttr.Examples
+			}
+			attributes = append(attributes, attr)
+			continue
+		}
+
+		if callExpr, ok := leaf.expr.(*ast.CallExpr); ok && isAttributeCarrier(callExpr) {
+			for _, arg := range callExpr.Args {
+				attributes = append(attributes, r.attributes(arg, leaf.pkg)...)
+			}
+		}
+	}
+
+	return attributes
+}
+
+// examples returns the literal values passed to an attribute constructor,
+// e.g. "kafka" for attribute.String("messaging.system", "kafka"). String
+// values are followed through variables and parameters like attribute keys,
+// and finite reports whether they are the only values the attribute takes.
+func (r *attrResolver) examples(expr ast.Expr, pkg *packages.Package) (values []string, finite bool) {
+	value := attributeValueExpr(expr, pkg)
+	if value == nil || pkg.TypesInfo == nil {
+		return nil, false
+	}
+
+	if lit, ok := value.(*ast.CompositeLit); ok {
+		for _, elt := range lit.Elts {
+			if v, ok := constantValue(elt, pkg); ok {
+				values = append(values, v)
+			}
+		}
+		return mergeStrings(nil, values), false
+	}
+
+	tv, ok := pkg.TypesInfo.Types[value]
+	if !ok {
+		return nil, false
+	}
+	basic, ok := tv.Type.Underlying().(*types.Basic)
+	isString := ok && basic.Info()&types.IsString != 0
+
+	if v, ok := constantValue(value, pkg); ok && v != "" {
+		return []string{v}, isString
+	}
+
+	if isString {
+		resolved, complete := r.resolveStrings(value, pkg)
+		// Empty strings are unset defaults rather than useful examples, and
+		// leave the attribute a plain string.
+		for _, v := range resolved {
+			if v == "" {
+				complete = false
+				continue
+			}
+			values = append(values, v)
+		}
+		return mergeStrings(nil, values), complete && len(values) > 0
+	}
+
+	return nil, false
+}
+
+// isAttributeCarrier reports whether a call wraps attributes passed as its
+// arguments, e.g. trace.WithAttributes or attribute.NewSet.
+func isAttributeCarrier(callExpr *ast.CallExpr) bool {
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	switch selExpr.Sel.Name {
+	case "WithAttributes", "WithAttributeSet", "NewSet":
+		return true
+	default:
+		return false
+	}
+}
+
+// expand follows expr back to the expressions that produce its value.
+func (r *attrResolver) expand(expr ast.Expr, pkg *packages.Package) []exprLeaf {
+	return r.expandResult(expr, pkg, -1)
+}
+
+// expandResult is expand for a single result of a multi-value expression.
+func (r *attrResolver) expandResult(expr ast.Expr, pkg *packages.Package, index int) []exprLeaf {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.expandResult(e.X, pkg, index)
+	case *ast.StarExpr:
+		return r.expand(e.X, pkg)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return r.expand(e.X, pkg)
+		}
+	case *ast.SliceExpr:
+		return r.expand(e.X, pkg)
+	case *ast.IndexExpr:
+		return r.expand(e.X, pkg)
+	case *ast.CompositeLit:
+		if isContainerLit(e, pkg) {
+			var leaves []exprLeaf
+			for _, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				leaves = append(leaves, r.expand(elt, pkg)...)
+			}
+			return leaves
+		}
+	case *ast.Ident:
+		if v, ok := identObject(e, pkg).(*types.Var); ok {
+			return r.expandVar(v)
+		}
+	case *ast.CallExpr:
+		if isBuiltin(e.Fun, pkg, "append") {
+			var leaves []exprLeaf
+			for _, arg := range e.Args {
+				leaves = append(leaves, r.expand(arg, pkg)...)
+			}
+			return leaves
+		}
+		if fn := calledFuncExpr(e.Fun, pkg); fn != nil {
+			if src, ok := r.funcs[fn.Origin().FullName()]; ok {
+				if leaves := r.expandFunc(src, index); len(leaves) > 0 {
+					return leaves
+				}
+			}
+		}
+	}
+
+	return []exprLeaf{{expr: expr, pkg: pkg}}
+}
+
+// expandVar follows every value assigned to a variable within its scope.
+func (r *attrResolver) expandVar(v *types.Var) []exprLeaf {
+	if leaves, ok := r.memo[v]; ok {
+		return leaves
+	}
+	if r.active[v] || v.Pkg() == nil {
+		return nil
+	}
+	pkg, ok := r.pkgs[v.Pkg().Path()]
+	if !ok {
+		return nil
+	}
+
+	r.active[v] = true
+	defer delete(r.active, v)
+
+	var leaves []exprLeaf
+	for _, node := range declScope(v, pkg) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch stmt := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range stmt.Lhs {
+					if identObject(lhs, pkg) != v {
+						continue
+					}
+					if len(stmt.Rhs) == len(stmt.Lhs) {
+						leaves = append(leaves, r.expand(stmt.Rhs[i], pkg)...)
+					} else if len(stmt.Rhs) == 1 {
+						leaves = append(leaves, r.expandResult(stmt.Rhs[0], pkg, i)...)
+					}
+				}
+			case *ast.ValueSpec:
+				for i, name := range stmt.Names {
+					if identObject(name, pkg) != v {
+						continue
+					}
+					if len(stmt.Values) == len(stmt.Names) {
+						leaves = append(leaves, r.expand(stmt.Values[i], pkg)...)
+					} else if len(stmt.Values) == 1 {
+						leaves = append(leaves, r.expandResult(stmt.Values[0], pkg, i)...)
+					}
+				}
+			case *ast.RangeStmt:
+				if stmt.Value != nil && identObject(stmt.Value, pkg) == v {
+					leaves = append(leaves, r.expand(stmt.X, pkg)...)
+				}
+			}
+			return true
+		})
+	}
+
+	r.memo[v] = leaves
+	return leaves
+}
+
+// expandFunc follows the values returned by a function declaration.
+func (r *attrResolver) expandFunc(src funcSource, index int) []exprLeaf {
+	key := funcResult{decl: src.decl, index: index}
+	if leaves, ok := r.memo[key]; ok {
+		return leaves
+	}
+	if r.active[key] {
+		return nil
+	}
+
+	r.active[key] = true
+	defer delete(r.active, key)
+
+	var namedResults []*ast.Ident
+	if src.decl.Type.Results != nil {
+		for _, field := range src.decl.Type.Results.List {
+			namedResults = append(namedResults, field.Names...)
+		}
+	}
+
+	var leaves []exprLeaf
+	ast.Inspect(src.decl.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return true
+		}
+
+		results := ret.Results
+		if len(results) == 0 {
+			for _, name := range namedResults {
+				results = append(results, name)
+			}
+		}
+
+		switch {
+		case index < 0:
+			for _, result := range results {
+				leaves = append(leaves, r.expand(result, src.pkg)...)
+			}
+		case len(results) == 1:
+			leaves = append(leaves, r.expandResult(results[0], src.pkg, index)...)
+		case index < len(results):
+			leaves = append(leaves, r.expand(results[index], src.pkg)...)
+		}
+		return true
+	})
+
+	r.memo[key] = leaves
+	return leaves
+}
+
+// stringValues resolves the constant strings expr can evaluate to, following
+// concatenation, local variables, function parameters (through every call
+// site in the module) and struct fields (through composite literals and
+// assignments).
+func (r *attrResolver) stringValues(expr ast.Expr, pkg *packages.Package) []string {
+	values, _ := r.resolveStrings(expr, pkg)
+	return values
+}
+
+// resolveStrings is stringValues also reporting whether every value expr can
+// take was resolved, i.e. whether the returned values are its full domain.
+func (r *attrResolver) resolveStrings(expr ast.Expr, pkg *packages.Package) ([]string, bool) {
+	if value, ok := constantString(expr, pkg); ok {
+		return []string{value}, true
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.resolveStrings(e.X, pkg)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return nil, false
+		}
+		xs, xComplete := r.resolveStrings(e.X, pkg)
+		ys, yComplete := r.resolveStrings(e.Y, pkg)
+		var values []string
+		for _, x := range xs {
+			for _, y := range ys {
+				values = append(values, x+y)
+			}
+		}
+		return values, xComplete && yComplete
+	case *ast.Ident:
+		v, ok := identObject(e, pkg).(*types.Var)
+		if !ok {
+			return nil, false
+		}
+
+		// Variables are guarded by expandVar; parameters are guarded here
+		// as call sites may pass them back in.
+		if fn, index, ok := r.paramOf(v); ok {
+			if r.active[v] {
+				return nil, false
+			}
+			r.active[v] = true
+			defer delete(r.active, v)
+
+			sites := r.callSites(fn)
+			var values []string
+			complete := len(sites) > 0
+			for _, site := range sites {
+				if index >= len(site.call.Args) {
+					complete = false
+					continue
+				}
+				siteValues, siteComplete := r.resolveStrings(site.call.Args[index], site.pkg)
+				values = append(values, siteValues...)
+				complete = complete && siteComplete
+			}
+			return values, complete
+		}
+
+		return r.resolveLeaves(r.expandVar(v))
+	case *ast.SelectorExpr:
+		field := exprObject(e, pkg)
+		v, ok := field.(*types.Var)
+		if !ok {
+			return nil, false
+		}
+		if !v.IsField() {
+			return r.resolveStrings(e.Sel, pkg)
+		}
+		if r.active[v] {
+			return nil, false
+		}
+		r.active[v] = true
+		defer delete(r.active, v)
+
+		return r.resolveLeaves(r.fieldValues(v))
+	case *ast.CallExpr:
+		leaves := r.expand(e, pkg)
+		if len(leaves) == 1 && leaves[0].expr == expr {
+			return nil, false
+		}
+		return r.resolveLeaves(leaves)
+	}
+
+	return nil, false
+}
+
+func (r *attrResolver) resolveLeaves(leaves []exprLeaf) ([]string, bool) {
+	var values []string
+	complete := len(leaves) > 0
+	for _, leaf := range leaves {
+		leafValues, leafComplete := r.resolveStrings(leaf.expr, leaf.pkg)
+		values = append(values, leafValues...)
+		complete = complete && leafComplete
+	}
+	return values, complete
+}
+
+// paramOf returns the function declaring v as a parameter and its index.
+func (r *attrResolver) paramOf(v *types.Var) (string, int, bool) {
+	for name, src := range r.funcs {
+		fn, ok := src.pkg.TypesInfo.Defs[src.decl.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		params := fn.Type().(*types.Signature).Params()
+		for i := 0; i < params.Len(); i++ {
+			if params.At(i) == v {
+				return name, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// callSites returns every call of the named function in the module.
+func (r *attrResolver) callSites(name string) []funcCall {
+	if r.calls == nil {
+		r.calls = make(map[string][]funcCall)
+		for _, pkg := range r.pkgs {
+			for _, file := range pkg.Syntax {
+				ast.Inspect(file, func(n ast.Node) bool {
+					if call, ok := n.(*ast.CallExpr); ok {
+						if fn := calledFuncExpr(call.Fun, pkg); fn != nil {
+							fullName := fn.Origin().FullName()
+							r.calls[fullName] = append(r.calls[fullName], funcCall{call: call, pkg: pkg})
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	return r.calls[name]
+}
+
+// fieldValues returns every value assigned to a struct field in the module.
+func (r *attrResolver) fieldValues(field *types.Var) []exprLeaf {
+	if r.fields == nil {
+		r.fields = make(map[types.Object][]exprLeaf)
+		for _, pkg := range r.pkgs {
+			if pkg.TypesInfo == nil {
+				continue
+			}
+			for _, file := range pkg.Syntax {
+				ast.Inspect(file, func(n ast.Node) bool {
+					switch node := n.(type) {
+					case *ast.KeyValueExpr:
+						if key, ok := node.Key.(*ast.Ident); ok {
+							if obj, ok := pkg.TypesInfo.Uses[key].(*types.Var); ok && obj.IsField() {
+								r.fields[obj] = append(r.fields[obj], exprLeaf{expr: node.Value, pkg: pkg})
+							}
+						}
+					case *ast.AssignStmt:
+						if len(node.Lhs) != len(node.Rhs) {
+							return true
+						}
+						for i, lhs := range node.Lhs {
+							if selExpr, ok := lhs.(*ast.SelectorExpr); ok {
+								if obj, ok := pkg.TypesInfo.Uses[selExpr.Sel].(*types.Var); ok && obj.IsField() {
+									r.fields[obj] = append(r.fields[obj], exprLeaf{expr: node.Rhs[i], pkg: pkg})
+								}
+							}
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	return r.fields[field]
+}
+
+// funcBody returns the body of a function literal or of a function declared
+// in the analyzed module, with the package it was loaded from.
+func (r *attrResolver) funcBody(expr ast.Expr, pkg *packages.Package) (*ast.BlockStmt, *packages.Package) {
+	if lit, ok := expr.(*ast.FuncLit); ok {
+		return lit.Body, pkg
+	}
+
+	if fn := calledFuncExpr(expr, pkg); fn != nil {
+		if src, ok := r.funcs[fn.Origin().FullName()]; ok {
+			return src.decl.Body, src.pkg
+		}
+	}
+
+	return nil, nil
+}
+
+// declScope returns the syntax a variable can be assigned in: its enclosing
+// function, or every file of the package for package-level variables.
+func declScope(v *types.Var, pkg *packages.Package) []ast.Node {
+	if v.Parent() == pkg.Types.Scope() {
+		nodes := make([]ast.Node, 0, len(pkg.Syntax))
+		for _, file := range pkg.Syntax {
+			nodes = append(nodes, file)
+		}
+		return nodes
+	}
+
+	for _, file := range pkg.Syntax {
+		if v.Pos() < file.FileStart || v.Pos() > file.FileEnd {
+			continue
+		}
+		path, _ := astutil.PathEnclosingInterval(file, v.Pos(), v.Pos())
+		for _, node := range path {
+			switch node.(type) {
+			case *ast.FuncDecl, *ast.FuncLit:
+				return []ast.Node{node}
+			}
+		}
+	}
+	return nil
+}
+
+// isContainerLit reports whether a composite literal builds a slice, array or map.
+func isContainerLit(lit *ast.CompositeLit, pkg *packages.Package) bool {
+	if pkg.TypesInfo != nil {
+		if t := pkg.TypesInfo.TypeOf(lit); t != nil {
+			switch t.Underlying().(type) {
+			case *types.Slice, *types.Array, *types.Map:
+				return true
+			}
+			return false
+		}
+	}
+	switch lit.Type.(type) {
+	case *ast.ArrayType, *ast.MapType:
+		return true
+	}
+	return false
+}
+
+func isBuiltin(fun ast.Expr, pkg *packages.Package, name string) bool {
+	ident, ok := fun.(*ast.Ident)
+	if !ok || ident.Name != name {
+		return false
+	}
+	if pkg.TypesInfo == nil {
+		return true
+	}
+	_, ok = pkg.TypesInfo.Uses[ident].(*types.Builtin)
+	return ok
+}
+
+// calledFuncExpr returns the function or method a call expression invokes.
+func calledFuncExpr(fun ast.Expr, pkg *packages.Package) *types.Func {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	switch f := fun.(type) {
+	case *ast.Ident:
+		fn, _ := pkg.TypesInfo.Uses[f].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		return calledFunc(f, pkg)
+	case *ast.IndexExpr:
+		return calledFuncExpr(f.X, pkg)
+	case *ast.IndexListExpr:
+		return calledFuncExpr(f.X, pkg)
+	case *ast.ParenExpr:
+		return calledFuncExpr(f.X, pkg)
+	}
+	return nil
+}
@@ -25,6 +25,23 @@ const (
 	MetricTypeGauge         MetricType = "gauge"
 )
 
+type StatusCode string
+
+const (
+	StatusCodeUnset StatusCode = "Unset"
+	StatusCodeError StatusCode = "Error"
+	StatusCodeOk    StatusCode = "Ok"
+)
+
+type RequirementLevel string
+
+const (
+	RequirementLevelRequired              RequirementLevel = "required"
+	RequirementLevelConditionallyRequired RequirementLevel = "conditionally_required"
+	RequirementLevelRecommended           RequirementLevel = "recommended"
+	RequirementLevelOptIn                 RequirementLevel = "opt_in"
+)
+
 type AttributeType string
 
 const (
@@ -32,6 +49,11 @@ const (
 	AttributeTypeLong    AttributeType = "int"
 	AttributeTypeBoolean AttributeType = "boolean"
 	AttributeTypeDouble  AttributeType = "double"
+
+	AttributeTypeStringArray  AttributeType = "string[]"
+	AttributeTypeLongArray    AttributeType = "int[]"
+	AttributeTypeBooleanArray AttributeType = "boolean[]"
+	AttributeTypeDoubleArray  AttributeType = "double[]"
 )
 
 type Stability string
@@ -42,30 +64,109 @@ const (
 	StabilityStable       Stability = "stable"
 )
 
+// DeprecationUncategorized is the Weaver deprecation reason used when a
+// library or signal is deprecated without being renamed or obsoleted.
+const DeprecationUncategorized = "uncategorized"
+
+// Deprecation marks a deprecated library or signal in Weaver's
+// `deprecated: {reason, renamed_to, note}` form.
+type Deprecation struct {
+	Reason    string `yaml:"reason"`
+	RenamedTo string `yaml:"renamed_to,omitempty"`
+	Note      string `yaml:"note,omitempty"`
+}
+
 type Group struct {
-	ID         string            `yaml:"id"`
-	Type       string            `yaml:"type"`
-	Name       string            `yaml:"display_name,omitempty"`
-	Stability  Stability         `yaml:"stability"`
-	Brief      string            `yaml:"brief"`
-	SpanKind   SpanKind          `yaml:"span_kind,omitempty"`
-	MetricName string            `yaml:"metric_name,omitempty"`
-	Instrument MetricType        `yaml:"instrument,omitempty"`
-	Unit       string            `yaml:"unit,omitempty"`
-	Attributes []AttributeRef    `yaml:"attributes,omitempty"`
+	ID          string                 `yaml:"id"`
+	Type        string                 `yaml:"type"`
+	Name        string                 `yaml:"display_name,omitempty"`
+	Stability   Stability              `yaml:"stability"`
+	Deprecated  *Deprecation           `yaml:"deprecated,omitempty"`
+	Brief       string                 `yaml:"brief"`
+	Note        string                 `yaml:"note,omitempty"`
+	SpanKind    SpanKind               `yaml:"span_kind,omitempty"`
+	MetricName  string                 `yaml:"metric_name,omitempty"`
+	Instrument  MetricType             `yaml:"instrument,omitempty"`
+	Unit        string                 `yaml:"unit,omitempty"`
+	EventName   string                 `yaml:"name,omitempty"`
+	Events      []string               `yaml:"events,omitempty"`
+	Attributes  []AttributeRef         `yaml:"attributes,omitempty"`
+	Annotations map[string]interface{} `yaml:"annotations,omitempty"`
 }
 
 type AttributeRef struct {
-	Ref              string `yaml:"ref"`
-	RequirementLevel string `yaml:"requirement_level,omitempty"`
+	Ref              string           `yaml:"ref"`
+	RequirementLevel RequirementLevel `yaml:"requirement_level,omitempty"`
+	Condition        string           `yaml:"-"`
+	Note             string           `yaml:"note,omitempty"`
+	Type             AttributeType    `yaml:"-"`
+	Examples         []string         `yaml:"-"`
+	Enum             bool             `yaml:"-"`
+}
+
+type attributeRefYAML struct {
+	Ref              string        `yaml:"ref"`
+	RequirementLevel yaml.Node     `yaml:"requirement_level,omitempty"`
+	Note             string        `yaml:"note,omitempty"`
+	Examples         []interface{} `yaml:"examples,omitempty"`
+}
+
+// MarshalYAML writes conditionally required attributes with Weaver's
+// `requirement_level: conditionally_required: <condition>` form. Semconv
+// attributes carry their examples on the ref, as they have no definition
+// in attributes.yaml.
+func (a AttributeRef) MarshalYAML() (interface{}, error) {
+	out := attributeRefYAML{
+		Ref:  a.Ref,
+		Note: a.Note,
+	}
+	if _, ok := GetSemconvAttribute(a.Ref); ok {
+		out.Examples = attributeExamples(a.Examples, a.Type)
+	}
+	var level interface{} = a.RequirementLevel
+	if a.RequirementLevel == RequirementLevelConditionallyRequired && a.Condition != "" {
+		level = map[RequirementLevel]string{a.RequirementLevel: a.Condition}
+	}
+	if a.RequirementLevel != "" {
+		if err := out.RequirementLevel.Encode(level); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (a *AttributeRef) UnmarshalYAML(value *yaml.Node) error {
+	var in attributeRefYAML
+	if err := value.Decode(&in); err != nil {
+		return err
+	}
+	*a = AttributeRef{
+		Ref:  in.Ref,
+		Note: in.Note,
+	}
+	if in.RequirementLevel.Kind == yaml.MappingNode {
+		var levels map[RequirementLevel]string
+		if err := in.RequirementLevel.Decode(&levels); err != nil {
+			return err
+		}
+		for level, condition := range levels {
+			a.RequirementLevel = level
+			a.Condition = condition
+		}
+		return nil
+	}
+	if in.RequirementLevel.Kind == 0 {
+		return nil
+	}
+	return in.RequirementLevel.Decode(&a.RequirementLevel)
 }
 
 type AttributeGroup struct {
-	ID         string           `yaml:"id"`
-	Type       string           `yaml:"type"`
-	Name       string           `yaml:"display_name"`
-	Brief      string           `yaml:"brief"`
-	Attributes []AttributeDef   `yaml:"attributes"`
+	ID         string         `yaml:"id"`
+	Type       string         `yaml:"type"`
+	Name       string         `yaml:"display_name"`
+	Brief      string         `yaml:"brief"`
+	Attributes []AttributeDef `yaml:"attributes"`
 }
 
 type AttributeDef struct {
@@ -74,6 +175,67 @@ type AttributeDef struct {
 	Brief     string        `yaml:"brief"`
 	Stability Stability     `yaml:"stability,omitempty"`
 	Examples  []interface{} `yaml:"examples,omitempty"`
+	Members   []EnumMember  `yaml:"-"`
+}
+
+type EnumMember struct {
+	ID        string    `yaml:"id"`
+	Value     string    `yaml:"value"`
+	Brief     string    `yaml:"brief,omitempty"`
+	Stability Stability `yaml:"stability,omitempty"`
+}
+
+type enumType struct {
+	Members []EnumMember `yaml:"members"`
+}
+
+type attributeDefYAML struct {
+	ID        string        `yaml:"id"`
+	Type      yaml.Node     `yaml:"type"`
+	Brief     string        `yaml:"brief"`
+	Stability Stability     `yaml:"stability,omitempty"`
+	Examples  []interface{} `yaml:"examples,omitempty"`
+}
+
+// MarshalYAML writes enum attributes with Weaver's `type: members: [...]` form.
+func (a AttributeDef) MarshalYAML() (interface{}, error) {
+	out := attributeDefYAML{
+		ID:        a.ID,
+		Brief:     a.Brief,
+		Stability: a.Stability,
+		Examples:  a.Examples,
+	}
+	var attrType interface{} = a.Type
+	if len(a.Members) > 0 {
+		attrType = enumType{Members: a.Members}
+	}
+	if err := out.Type.Encode(attrType); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (a *AttributeDef) UnmarshalYAML(value *yaml.Node) error {
+	var in attributeDefYAML
+	if err := value.Decode(&in); err != nil {
+		return err
+	}
+	*a = AttributeDef{
+		ID:        in.ID,
+		Brief:     in.Brief,
+		Stability: in.Stability,
+		Examples:  in.Examples,
+	}
+	if in.Type.Kind == yaml.MappingNode {
+		var enum enumType
+		if err := in.Type.Decode(&enum); err != nil {
+			return err
+		}
+		a.Type = AttributeTypeString
+		a.Members = enum.Members
+		return nil
+	}
+	return in.Type.Decode(&a.Type)
 }
 
 type Attribute struct {
@@ -83,6 +245,15 @@ type Attribute struct {
 	Type      AttributeType `yaml:"type,omitempty"`
 	Stability Stability     `yaml:"stability,omitempty"`
 	Examples  []string      `yaml:"examples,omitempty"`
+	Enum      bool          `yaml:"-"`
+	When      string        `yaml:"-"`
+	Condition string        `yaml:"-"`
+	// Start is set for attributes passed to Tracer.Start through
+	// trace.WithAttributes.
+	Start bool `yaml:"-"`
+	// RequirementLevel is set for attributes taken from a semantic
+	// convention, whose Condition is then the convention's own text.
+	RequirementLevel RequirementLevel `yaml:"-"`
 }
 
 type Telemetry struct {
@@ -92,17 +263,41 @@ type Telemetry struct {
 }
 
 type Span struct {
-	Kind       SpanKind    `yaml:"kind,omitempty"`
-	Attributes []Attribute `yaml:"attributes,omitempty"`
+	Name       string       `yaml:"name,omitempty"`
+	Kind       SpanKind     `yaml:"kind,omitempty"`
+	Source     string       `yaml:"source,omitempty"`
+	Attributes []Attribute  `yaml:"attributes,omitempty"`
+	Events     []Event      `yaml:"events,omitempty"`
+	Status     []SpanStatus `yaml:"status,omitempty"`
+	When       string       `yaml:"-"`
 }
 
-type Metric struct {
+type SpanStatus struct {
+	Code        StatusCode `yaml:"code"`
+	Description bool       `yaml:"description,omitempty"`
+	Condition   string     `yaml:"condition,omitempty"`
+}
+
+type Event struct {
 	Name       string      `yaml:"name"`
-	Type       MetricType  `yaml:"type"`
-	Unit       string      `yaml:"unit,omitempty"`
+	Source     string      `yaml:"source,omitempty"`
+	SemconvRef string      `yaml:"semconv_ref,omitempty"`
 	Attributes []Attribute `yaml:"attributes,omitempty"`
 }
 
+type Metric struct {
+	Name             string      `yaml:"name"`
+	Type             MetricType  `yaml:"type"`
+	Instrument       string      `yaml:"instrument,omitempty"`
+	Observable       bool        `yaml:"observable,omitempty"`
+	Description      string      `yaml:"description,omitempty"`
+	Unit             string      `yaml:"unit,omitempty"`
+	BucketBoundaries []float64   `yaml:"bucket_boundaries,omitempty,flow"`
+	Attributes       []Attribute `yaml:"attributes,omitempty"`
+	SemconvRef       string      `yaml:"semconv_ref,omitempty"`
+	When             string      `yaml:"-"`
+}
+
 func (a Attribute) MarshalYAML() (interface{}, error) {
 	node := &yaml.Node{
 		Kind: yaml.MappingNode,
@@ -113,9 +308,173 @@ func (a Attribute) MarshalYAML() (interface{}, error) {
 			{Kind: yaml.ScalarNode, Value: string(a.Type)},
 		},
 	}
+	if len(a.Examples) > 0 {
+		examples := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, example := range a.Examples {
+			value := &yaml.Node{Kind: yaml.ScalarNode, Value: example}
+			// Tagged string examples are quoted when they would otherwise
+			// read back as numbers or booleans, e.g. "200" or "true".
+			if a.Type == AttributeTypeString || a.Type == AttributeTypeStringArray {
+				value.Tag = "!!str"
+			}
+			examples.Content = append(examples.Content, value)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "examples"}, examples)
+	}
 	return node, nil
 }
 
+type Library struct {
+	Name            string         `yaml:"name"`
+	DisplayName     string         `yaml:"display_name,omitempty"`
+	Description     string         `yaml:"description,omitempty"`
+	Module          string         `yaml:"module"`
+	ImportPath      string         `yaml:"import_path,omitempty"`
+	Version         string         `yaml:"version,omitempty"`
+	Stability       Stability      `yaml:"stability,omitempty"`
+	Deprecated      *Deprecation   `yaml:"deprecated,omitempty"`
+	URL             string         `yaml:"url,omitempty"`
+	Source          string         `yaml:"source,omitempty"`
+	Target          *Dependency    `yaml:"target,omitempty"`
+	GoVersion       string         `yaml:"go_version,omitempty"`
+	OTel            []Dependency   `yaml:"otel,omitempty"`
+	SemconvVersions []string       `yaml:"semconv_versions,omitempty,flow"`
+	Scopes          []Scope        `yaml:"scopes,omitempty"`
+	Propagation     *Propagation   `yaml:"propagation,omitempty"`
+	Spans           []SpanSummary  `yaml:"spans,omitempty"`
+	SemconvMetrics  []SemconvRef   `yaml:"semconv_metrics,omitempty"`
+	Groups          []string       `yaml:"groups,omitempty"`
+	Telemetry       []Telemetry    `yaml:"-"`
+	Configuration   []ConfigOption `yaml:"configuration,omitempty"`
+	Environment     []EnvVar       `yaml:"environment_variables,omitempty"`
+}
+
+// Dependency is a module required by an instrumentation module. The version
+// is the minimum it supports; standard library targets have none.
+type Dependency struct {
+	Path    string `yaml:"path"`
+	Version string `yaml:"version,omitempty"`
+}
+
+// SemconvRef is a semantic convention the library emits as defined, e.g.
+// the http.server.request.duration metric of metric.http.server.request.duration.
+type SemconvRef struct {
+	Name       string       `yaml:"name"`
+	Ref        string       `yaml:"ref"`
+	Stability  Stability    `yaml:"stability,omitempty"`
+	Deprecated *Deprecation `yaml:"deprecated,omitempty"`
+}
+
+type Propagation struct {
+	Extract           []Carrier `yaml:"extract,omitempty"`
+	Inject            []Carrier `yaml:"inject,omitempty"`
+	DefaultPropagator string    `yaml:"default_propagator,omitempty"`
+	Option            string    `yaml:"option,omitempty"`
+}
+
+type Carrier struct {
+	Type   string `yaml:"type"`
+	Kind   string `yaml:"kind,omitempty"`
+	Source string `yaml:"source,omitempty"`
+}
+
+type Scope struct {
+	Name          string   `yaml:"name"`
+	Version       string   `yaml:"version,omitempty"`
+	VersionSource string   `yaml:"version_source,omitempty"`
+	SchemaURL     string   `yaml:"schema_url,omitempty"`
+	Signals       []string `yaml:"signals,flow"`
+	Source        string   `yaml:"source,omitempty"`
+}
+
+type SemconvLag struct {
+	Name     string   `yaml:"name"`
+	Module   string   `yaml:"module"`
+	Versions []string `yaml:"semconv_versions,flow"`
+}
+
+type EnvVar struct {
+	Name   string   `yaml:"name"`
+	Values []string `yaml:"values,omitempty"`
+	Source string   `yaml:"source,omitempty"`
+}
+
+type ConfigOption struct {
+	Name        string            `yaml:"name"`
+	Type        string            `yaml:"type"`
+	Description string            `yaml:"description,omitempty"`
+	Parameters  []ConfigParameter `yaml:"parameters,omitempty"`
+	Default     string            `yaml:"default,omitempty"`
+}
+
+type ConfigParameter struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+type SpanSummary struct {
+	Name          string       `yaml:"name,omitempty"`
+	Kind          SpanKind     `yaml:"kind,omitempty"`
+	Status        []SpanStatus `yaml:"status,omitempty"`
+	RecordsErrors bool         `yaml:"records_errors,omitempty"`
+}
+
+// InstrumentationList is the instrumentation list format of the OpenTelemetry
+// Ecosystem Explorer, as published for the Java agent.
+type InstrumentationList struct {
+	FileFormat float64                             `yaml:"file_format"`
+	Libraries  map[string][]InstrumentationLibrary `yaml:"libraries"`
+}
+
+type InstrumentationLibrary struct {
+	Name             string                     `yaml:"name"`
+	DisplayName      string                     `yaml:"display_name,omitempty"`
+	Description      string                     `yaml:"description,omitempty"`
+	LibraryLink      string                     `yaml:"library_link,omitempty"`
+	SourcePath       string                     `yaml:"source_path,omitempty"`
+	MinimumGoVersion string                     `yaml:"minimum_go_version,omitempty"`
+	Scope            InstrumentationScope       `yaml:"scope"`
+	TargetVersions   map[string][]string        `yaml:"target_versions,omitempty"`
+	Configurations   []InstrumentationConfig    `yaml:"configurations,omitempty"`
+	Telemetry        []InstrumentationTelemetry `yaml:"telemetry,omitempty"`
+}
+
+type InstrumentationScope struct {
+	Name      string `yaml:"name"`
+	SchemaURL string `yaml:"schema_url,omitempty"`
+}
+
+type InstrumentationConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Type        string `yaml:"type,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+}
+
+type InstrumentationTelemetry struct {
+	When    string                  `yaml:"when"`
+	Metrics []InstrumentationMetric `yaml:"metrics,omitempty"`
+	Spans   []InstrumentationSpan   `yaml:"spans,omitempty"`
+}
+
+type InstrumentationMetric struct {
+	Name        string                     `yaml:"name"`
+	Description string                     `yaml:"description,omitempty"`
+	Type        string                     `yaml:"type"`
+	Unit        string                     `yaml:"unit,omitempty"`
+	Attributes  []InstrumentationAttribute `yaml:"attributes,omitempty"`
+}
+
+type InstrumentationSpan struct {
+	SpanKind   string                     `yaml:"span_kind"`
+	Attributes []InstrumentationAttribute `yaml:"attributes,omitempty"`
+}
+
+type InstrumentationAttribute struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
 type Stats struct {
 	LibrariesWithTelemetry           int
 	LibrariesWithSemanticConventions int
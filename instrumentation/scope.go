@@ -0,0 +1,123 @@
+package instrumentation
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const otelPkgPath = "go.opentelemetry.io/otel"
+
+// extractScopes lists the instrumentation scopes the module creates through
+// TracerProvider.Tracer, MeterProvider.Meter or the otel.Tracer and otel.Meter
+// helpers, with the version and schema URL options passed alongside them.
+func extractScopes(pkgs []*packages.Package, resolver *attrResolver) []Scope {
+	scopeMap := make(map[string]*Scope)
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				callExpr, ok := n.(*ast.CallExpr)
+				if !ok || len(callExpr.Args) == 0 {
+					return true
+				}
+
+				signal := scopeSignal(callExpr, pkg)
+				if signal == "" {
+					return true
+				}
+
+				for _, name := range resolver.stringValues(callExpr.Args[0], pkg) {
+					scope, ok := scopeMap[name]
+					if !ok {
+						scope = &Scope{Name: name, Source: callSite(callExpr, pkg)}
+						scopeMap[name] = scope
+					}
+					scope.Signals = mergeStrings(scope.Signals, []string{signal})
+					for _, arg := range callExpr.Args[1:] {
+						for _, opt := range resolver.expand(arg, pkg) {
+							parseScopeOption(opt.expr, opt.pkg, resolver, scope)
+						}
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	var scopes []Scope
+	for _, scope := range scopeMap {
+		sort.Strings(scope.Signals)
+		scopes = append(scopes, *scope)
+	}
+
+	sort.Slice(scopes, func(i, j int) bool {
+		return scopes[i].Name < scopes[j].Name
+	})
+
+	return scopes
+}
+
+// scopeSignal returns the signal a scope is created for, or "" when the call
+// does not create a tracer or meter.
+func scopeSignal(callExpr *ast.CallExpr, pkg *packages.Package) string {
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	if fn := calledFuncExpr(callExpr.Fun, pkg); fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == otelPkgPath {
+		switch fn.Name() {
+		case "Tracer":
+			return "traces"
+		case "Meter":
+			return "metrics"
+		}
+		return ""
+	}
+
+	switch selExpr.Sel.Name {
+	case "Tracer":
+		if receiverImplements(selExpr, pkg, tracePkgPath, "TracerProvider") {
+			return "traces"
+		}
+	case "Meter":
+		if receiverImplements(selExpr, pkg, metricPkgPath, "MeterProvider") {
+			return "metrics"
+		}
+	}
+	return ""
+}
+
+// parseScopeOption records the version and schema URL set by
+// WithInstrumentationVersion and WithSchemaURL tracer or meter options.
+func parseScopeOption(expr ast.Expr, pkg *packages.Package, resolver *attrResolver, scope *Scope) {
+	callExpr, ok := expr.(*ast.CallExpr)
+	if !ok || len(callExpr.Args) != 1 {
+		return
+	}
+
+	fn := calledFuncExpr(callExpr.Fun, pkg)
+	if fn == nil || fn.Pkg() == nil || (fn.Pkg().Path() != tracePkgPath && fn.Pkg().Path() != metricPkgPath) {
+		return
+	}
+
+	values := resolver.stringValues(callExpr.Args[0], pkg)
+	switch fn.Name() {
+	case "WithInstrumentationVersion":
+		scope.VersionSource = types.ExprString(callExpr.Args[0])
+		if len(values) > 0 {
+			scope.Version = values[0]
+		}
+	case "WithSchemaURL":
+		if len(values) > 0 {
+			scope.SchemaURL = values[0]
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package instrumentation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractScopes(t *testing.T) {
+	t.Run("extractScopes - records scope name, version and schema URL", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := `package testpkg
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name.
+const ScopeName = "example.com/testpkg"
+
+type config struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+}
+
+func newInstruments(cfg config) (trace.Tracer, metric.Meter) {
+	tracer := cfg.TracerProvider.Tracer(
+		ScopeName,
+		trace.WithInstrumentationVersion(Version()),
+		trace.WithSchemaURL(semconv.SchemaURL),
+	)
+	opts := []metric.MeterOption{metric.WithInstrumentationVersion(Version())}
+	meter := cfg.MeterProvider.Meter(ScopeName, opts...)
+	return tracer, meter
+}
+
+func global() trace.Tracer {
+	return otel.Tracer(ScopeName + "/global")
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "test.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		version := `package testpkg
+
+// Version is the current release version of the instrumentation.
+func Version() string {
+	return "0.63.0"
+}
+`
+		if err := os.WriteFile(filepath.Join(tmpDir, "version.go"), []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		goModContent := `module example.com/testpkg
+
+go 1.24
+
+require go.opentelemetry.io/otel v1.38.0
+`
+		goModPath := filepath.Join(tmpDir, "go.mod")
+		if err := os.WriteFile(goModPath, []byte(goModContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		analysis, err := AnalyzePackage(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzePackage() error = %v", err)
+		}
+
+		want := []Scope{
+			{
+				Name:          "example.com/testpkg",
+				Version:       "0.63.0",
+				VersionSource: "Version()",
+				SchemaURL:     "https://opentelemetry.io/schemas/1.37.0",
+				Signals:       []string{"metrics", "traces"},
+				Source:        "test.go:19",
+			},
+			{
+				Name:    "example.com/testpkg/global",
+				Signals: []string{"traces"},
+				Source:  "test.go:30",
+			},
+		}
+
+		if !reflect.DeepEqual(analysis.Scopes, want) {
+			t.Errorf("Scopes = %+v, want %+v", analysis.Scopes, want)
+		}
+	})
+}
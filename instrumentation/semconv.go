@@ -1,8 +1,10 @@
 package instrumentation
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -10,26 +12,62 @@ import (
 
 // SemconvAttribute represents an attribute from the semantic conventions registry.
 type SemconvAttribute struct {
-	ID    string
-	Brief string
-	Type  string
+	ID         string
+	Brief      string
+	Type       string
+	Examples   []string
+	Stability  Stability
+	Deprecated *Deprecation
 }
 
 // SemconvMetric represents a metric from the semantic conventions registry.
 type SemconvMetric struct {
+	ID         string
+	Name       string
+	Stability  Stability
+	Deprecated *Deprecation
+}
+
+// SemconvEvent represents an event from the semantic conventions registry.
+type SemconvEvent struct {
+	ID   string
 	Name string
 }
 
+// SemconvSpan represents a span group from the semantic conventions registry,
+// e.g. span.http.server, with the attributes it references.
+type SemconvSpan struct {
+	ID         string
+	SpanKind   SpanKind
+	Attributes []AttributeRef
+}
+
 // semconvRegistry holds loaded semantic convention attributes.
 var semconvRegistry map[string]SemconvAttribute
 
 // semconvMetrics holds loaded semantic convention metric names.
 var semconvMetrics map[string]SemconvMetric
 
+// semconvSpans holds loaded semantic convention span groups by ID.
+var semconvSpans map[string]SemconvSpan
+
+// semconvEvents holds loaded semantic convention events by name.
+var semconvEvents map[string]SemconvEvent
+
+// semconvGroup is a group other groups can extend.
+type semconvGroup struct {
+	Type       string
+	SpanKind   SpanKind
+	Extends    string
+	Attributes []AttributeRef
+}
+
 // LoadSemconv loads attribute and metric definitions from the semantic conventions registry.
 func LoadSemconv(semconvPath string) error {
 	semconvRegistry = make(map[string]SemconvAttribute)
 	semconvMetrics = make(map[string]SemconvMetric)
+	semconvSpans = make(map[string]SemconvSpan)
+	semconvEvents = make(map[string]SemconvEvent)
 
 	if _, err := os.Stat(semconvPath); os.IsNotExist(err) {
 		return nil
@@ -49,15 +87,50 @@ func LoadSemconv(semconvPath string) error {
 		return err
 	}
 
+	groups := make(map[string]semconvGroup)
 	for _, file := range files {
-		if err := parseSemconvFile(file); err != nil {
+		if err := parseSemconvFile(file, groups); err != nil {
 			continue
 		}
 	}
 
+	for id, group := range groups {
+		if group.Type != "span" {
+			continue
+		}
+		semconvSpans[id] = SemconvSpan{
+			ID:         id,
+			SpanKind:   group.SpanKind,
+			Attributes: groupAttributes(id, groups, make(map[string]bool)),
+		}
+	}
+
 	return nil
 }
 
+// groupAttributes returns the attributes of a group including those of the
+// groups it extends; the extending group's requirement levels win.
+func groupAttributes(id string, groups map[string]semconvGroup, seen map[string]bool) []AttributeRef {
+	group, ok := groups[id]
+	if !ok || seen[id] {
+		return nil
+	}
+	seen[id] = true
+
+	var attrs []AttributeRef
+	index := make(map[string]int)
+	for _, attr := range group.Attributes {
+		index[attr.Ref] = len(attrs)
+		attrs = append(attrs, attr)
+	}
+	for _, attr := range groupAttributes(group.Extends, groups, seen) {
+		if _, ok := index[attr.Ref]; !ok {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
 // parseAttributeType extracts the type from an attribute map.
 func parseAttributeType(attrMap map[string]interface{}) string {
 	if typeVal, ok := attrMap["type"].(string); ok {
@@ -71,8 +144,45 @@ func parseAttributeType(attrMap map[string]interface{}) string {
 	return ""
 }
 
-// parseSemconvFile parses a single semantic convention YAML file.
-func parseSemconvFile(filePath string) error {
+// parseAttributeExamples extracts the examples from an attribute map, which
+// may be a single value or a list of values.
+func parseAttributeExamples(attrMap map[string]interface{}) []string {
+	switch examples := attrMap["examples"].(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var values []string
+		for _, example := range examples {
+			values = append(values, fmt.Sprint(example))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(examples)}
+	}
+}
+
+// parseDeprecation reads a registry `deprecated` field, either Weaver's
+// {reason, renamed_to, note} form or the older plain note.
+func parseDeprecation(value interface{}) *Deprecation {
+	switch deprecated := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		reason, _ := deprecated["reason"].(string)
+		renamedTo, _ := deprecated["renamed_to"].(string)
+		note, _ := deprecated["note"].(string)
+		if reason == "" {
+			reason = DeprecationUncategorized
+		}
+		return &Deprecation{Reason: reason, RenamedTo: renamedTo, Note: strings.TrimSpace(note)}
+	default:
+		return &Deprecation{Reason: DeprecationUncategorized, Note: strings.TrimSpace(fmt.Sprint(deprecated))}
+	}
+}
+
+// parseSemconvFile parses a single semantic convention YAML file, collecting
+// span groups and the groups they may extend into groups.
+func parseSemconvFile(filePath string, groups map[string]semconvGroup) error {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
@@ -82,7 +192,10 @@ func parseSemconvFile(filePath string) error {
 		Groups []struct {
 			ID         string                   `yaml:"id"`
 			Type       string                   `yaml:"type"`
+			Name       string                   `yaml:"name"`
 			MetricName string                   `yaml:"metric_name"`
+			Stability  Stability                `yaml:"stability"`
+			Deprecated interface{}              `yaml:"deprecated"`
 			Attributes []map[string]interface{} `yaml:"attributes"`
 		} `yaml:"groups"`
 	}
@@ -91,10 +204,48 @@ func parseSemconvFile(filePath string) error {
 		return err
 	}
 
+	// Attribute references are decoded separately to reuse AttributeRef's
+	// requirement level parsing.
+	var refs struct {
+		Groups []struct {
+			ID         string         `yaml:"id"`
+			Type       string         `yaml:"type"`
+			SpanKind   SpanKind       `yaml:"span_kind"`
+			Extends    string         `yaml:"extends"`
+			Attributes []AttributeRef `yaml:"attributes"`
+		} `yaml:"groups"`
+	}
+	if err := yaml.Unmarshal(data, &refs); err == nil {
+		for _, group := range refs.Groups {
+			var attrs []AttributeRef
+			for _, attr := range group.Attributes {
+				if attr.Ref != "" {
+					attrs = append(attrs, attr)
+				}
+			}
+			groups[group.ID] = semconvGroup{
+				Type:       group.Type,
+				SpanKind:   group.SpanKind,
+				Extends:    group.Extends,
+				Attributes: attrs,
+			}
+		}
+	}
+
 	for _, group := range doc.Groups {
 		if group.Type == "metric" && group.MetricName != "" {
 			semconvMetrics[group.MetricName] = SemconvMetric{
-				Name: group.MetricName,
+				ID:         group.ID,
+				Name:       group.MetricName,
+				Stability:  group.Stability,
+				Deprecated: parseDeprecation(group.Deprecated),
+			}
+		}
+
+		if group.Type == "event" && group.Name != "" {
+			semconvEvents[group.Name] = SemconvEvent{
+				ID:   group.ID,
+				Name: group.Name,
 			}
 		}
 
@@ -114,11 +265,15 @@ func parseSemconvFile(filePath string) error {
 
 			brief, _ := attrMap["brief"].(string)
 			attrType := parseAttributeType(attrMap)
+			stability, _ := attrMap["stability"].(string)
 
 			semconvRegistry[id] = SemconvAttribute{
-				ID:    id,
-				Brief: brief,
-				Type:  mapSemconvType(attrType),
+				ID:         id,
+				Brief:      brief,
+				Type:       mapSemconvType(attrType),
+				Examples:   parseAttributeExamples(attrMap),
+				Stability:  Stability(stability),
+				Deprecated: parseDeprecation(attrMap["deprecated"]),
 			}
 		}
 	}
@@ -128,15 +283,9 @@ func parseSemconvFile(filePath string) error {
 
 // mapSemconvType converts semconv types to our AttributeType format.
 func mapSemconvType(semconvType string) string {
-	switch strings.ToLower(semconvType) {
-	case "string", "string[]":
-		return "string"
-	case "int", "int[]":
-		return "int"
-	case "double", "double[]":
-		return "double"
-	case "boolean", "boolean[]":
-		return "boolean"
+	switch t := strings.ToLower(semconvType); t {
+	case "string", "string[]", "int", "int[]", "double", "double[]", "boolean", "boolean[]":
+		return t
 	default:
 		return "string"
 	}
@@ -159,3 +308,38 @@ func GetSemconvMetric(name string) (SemconvMetric, bool) {
 	metric, ok := semconvMetrics[name]
 	return metric, ok
 }
+
+// GetSemconvEvent retrieves an event from the semconv registry.
+func GetSemconvEvent(name string) (SemconvEvent, bool) {
+	if semconvEvents == nil {
+		return SemconvEvent{}, false
+	}
+	event, ok := semconvEvents[name]
+	return event, ok
+}
+
+// GetSemconvSpans returns the registry span groups of kind in namespace,
+// keeping the most general ones, e.g. span.db.client over
+// span.db.mongodb.client.
+func GetSemconvSpans(kind SpanKind, namespace string) []SemconvSpan {
+	var spans []SemconvSpan
+	depth := 0
+	for _, span := range semconvSpans {
+		segments := strings.Split(span.ID, ".")
+		if span.SpanKind != kind || len(segments) < 2 || segments[1] != namespace {
+			continue
+		}
+		switch {
+		case len(spans) == 0 || len(segments) < depth:
+			spans = []SemconvSpan{span}
+			depth = len(segments)
+		case len(segments) == depth:
+			spans = append(spans, span)
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].ID < spans[j].ID
+	})
+	return spans
+}
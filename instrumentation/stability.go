@@ -0,0 +1,125 @@
+package instrumentation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// versionsFile is the multimod release file contrib keeps at its root,
+// assigning every module to a versioned module set.
+const versionsFile = "versions.yaml"
+
+const deprecatedPrefix = "Deprecated:"
+
+// moduleSetVersions maps every module listed in the repository's
+// versions.yaml to the version of its module set. It is nil when the
+// repository has no versions.yaml.
+func moduleSetVersions(repoRoot string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(repoRoot, versionsFile))
+	if err != nil {
+		return nil
+	}
+
+	var versions struct {
+		ModuleSets map[string]struct {
+			Version string   `yaml:"version"`
+			Modules []string `yaml:"modules"`
+		} `yaml:"module-sets"`
+	}
+	if err := yaml.Unmarshal(data, &versions); err != nil {
+		return nil
+	}
+
+	moduleVersions := make(map[string]string)
+	for _, set := range versions.ModuleSets {
+		for _, mod := range set.Modules {
+			moduleVersions[mod] = set.Version
+		}
+	}
+	return moduleVersions
+}
+
+// scopeVersion returns the instrumentation version a library reports on its
+// scopes, e.g. v0.63.0 from Version().
+func scopeVersion(scopes []Scope) string {
+	for _, scope := range scopes {
+		if scope.Version != "" {
+			return "v" + strings.TrimPrefix(scope.Version, "v")
+		}
+	}
+	return ""
+}
+
+// moduleStability derives a module's stability from its version: v1 and
+// later releases are stable, v0 and pre-releases are in development. Without
+// a version the major version suffix of the module path is used.
+func moduleStability(modulePath, version string) Stability {
+	if version == "" {
+		if _, major, ok := module.SplitPathVersion(modulePath); ok && major != "" {
+			version = strings.TrimLeft(major, "/.") + ".0.0"
+		}
+	}
+	if !semver.IsValid(version) || semver.Prerelease(version) != "" || semver.Major(version) == "v0" {
+		return StabilityDevelopment
+	}
+	return StabilityStable
+}
+
+// deprecationNotice returns the paragraph of a doc comment starting with
+// "Deprecated:", without the prefix.
+func deprecationNotice(doc string) string {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if strings.HasPrefix(paragraph, deprecatedPrefix) {
+			return strings.Join(strings.Fields(strings.TrimPrefix(paragraph, deprecatedPrefix)), " ")
+		}
+	}
+	return ""
+}
+
+// minStability returns the less stable of two levels. Levels below stable,
+// such as the registry's alpha or release_candidate, are development.
+func minStability(a, b Stability) Stability {
+	if a == StabilityStable && b == StabilityStable {
+		return StabilityStable
+	}
+	return StabilityDevelopment
+}
+
+// applyStability sets each group's stability to the library's, lowered to
+// that of the semconv attributes it references. Groups of a deprecated
+// library are marked deprecated; deprecated semconv attributes a group
+// records are only listed in its note, as it may record their replacements
+// too.
+func applyStability(groups []Group, stability Stability, deprecated *Deprecation) {
+	for i := range groups {
+		groups[i].Stability = stability
+		groups[i].Deprecated = deprecated
+		var deprecatedAttrs []string
+		for _, attr := range groups[i].Attributes {
+			semconvAttr, ok := GetSemconvAttribute(attr.Ref)
+			if !ok {
+				continue
+			}
+			if semconvAttr.Stability != "" {
+				groups[i].Stability = minStability(groups[i].Stability, semconvAttr.Stability)
+			}
+			if semconvAttr.Deprecated != nil {
+				deprecatedAttrs = append(deprecatedAttrs, "`"+attr.Ref+"`")
+			}
+		}
+
+		if len(deprecatedAttrs) > 0 {
+			note := "Records deprecated semantic convention attributes " + strings.Join(deprecatedAttrs, ", ") + "."
+			if groups[i].Note != "" {
+				note = groups[i].Note + "\n" + note
+			}
+			groups[i].Note = note
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package instrumentation
+
+import "testing"
+
+func TestModuleStability(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		version    string
+		want       Stability
+	}{
+		{"v0 module set", "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp", "v0.63.0", StabilityDevelopment},
+		{"v1 module set", "go.opentelemetry.io/contrib/propagators/b3", "v1.38.0", StabilityStable},
+		{"pre-release", "go.opentelemetry.io/contrib/propagators/b3", "v1.0.0-rc.1", StabilityDevelopment},
+		{"major version suffix", "example.com/instrumentation/otelfoo/v2", "", StabilityStable},
+		{"unknown version", "example.com/instrumentation/otelfoo", "", StabilityDevelopment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moduleStability(tt.modulePath, tt.version); got != tt.want {
+				t.Errorf("moduleStability(%q, %q) = %v, want %v", tt.modulePath, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"no notice", "Package otelfoo instruments foo.\n", ""},
+		{"notice paragraph", "Package otelfoo instruments foo.\n\nDeprecated: use otelbar\ninstead.\n", "use otelbar instead."},
+		{"notice not at paragraph start", "Package otelfoo is not Deprecated: at all.\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deprecationNotice(tt.doc); got != tt.want {
+				t.Errorf("deprecationNotice(%q) = %q, want %q", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinStability(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Stability
+		want Stability
+	}{
+		{"both stable", StabilityStable, StabilityStable, StabilityStable},
+		{"stable and development", StabilityStable, StabilityDevelopment, StabilityDevelopment},
+		{"release candidate", StabilityStable, "release_candidate", StabilityDevelopment},
+		{"alpha", "alpha", StabilityStable, StabilityDevelopment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := minStability(tt.a, tt.b); got != tt.want {
+				t.Errorf("minStability(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
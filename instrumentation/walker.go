@@ -3,21 +3,16 @@ package instrumentation
 import (
 	"os"
 	"path/filepath"
-	"strings"
 )
 
-var omitDirectories = []string{
-	"example",
-	"internal",
-	"test",
-}
-
 type Package struct {
 	Path      string
 	Module    string
 	GoModPath string
 }
 
+// Walk finds every module under rootPath. Example and test modules are kept;
+// AnalyzePackage skips them by package type.
 func Walk(rootPath string) ([]Package, error) {
 	var packages []Package
 
@@ -32,10 +27,6 @@ func Walk(rootPath string) ([]Package, error) {
 				return err
 			}
 
-			if omitDirectory(relPath) {
-				return nil
-			}
-
 			pkg := Package{
 				Path:      relPath,
 				GoModPath: path,
@@ -48,12 +39,3 @@ func Walk(rootPath string) ([]Package, error) {
 
 	return packages, err
 }
-
-func omitDirectory(relPath string) bool {
-	for _, dir := range omitDirectories {
-		if strings.Contains(relPath, dir) {
-			return true
-		}
-	}
-	return false
-}
@@ -37,7 +37,7 @@ func TestWalk(t *testing.T) {
 		}
 	})
 
-	t.Run("walker - excludes internal directories", func(t *testing.T) {
+	t.Run("walker - finds modules regardless of directory name", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
 		dirs := []string{
@@ -63,12 +63,8 @@ func TestWalk(t *testing.T) {
 			t.Fatalf("Walk() error = %v", err)
 		}
 
-		if len(packages) != 1 {
-			t.Errorf("Walk() found %d packages, want 1", len(packages))
-		}
-
-		if packages[0].Path != "valid/package" {
-			t.Errorf("Walk() found package at %v, want valid/package", packages[0].Path)
+		if len(packages) != len(dirs) {
+			t.Errorf("Walk() found %d packages, want %d", len(packages), len(dirs))
 		}
 	})
 
@@ -85,41 +81,3 @@ func TestWalk(t *testing.T) {
 		}
 	})
 }
-
-func TestOmitDirectory(t *testing.T) {
-	tests := []struct {
-		name    string
-		relPath string
-		want    bool
-	}{
-		{
-			name:    "omitDirectory - allows normal path",
-			relPath: "github.com/gin-gonic/gin/otelgin",
-			want:    false,
-		},
-		{
-			name:    "omitDirectory - excludes internal",
-			relPath: "github.com/gin-gonic/gin/internal/helper",
-			want:    true,
-		},
-		{
-			name:    "omitDirectory - excludes test",
-			relPath: "github.com/gin-gonic/gin/test",
-			want:    true,
-		},
-		{
-			name:    "omitDirectory - excludes example",
-			relPath: "github.com/gin-gonic/gin/example",
-			want:    true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := omitDirectory(tt.relPath)
-			if got != tt.want {
-				t.Errorf("omitDirectory() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
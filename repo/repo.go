@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -97,6 +98,13 @@ func (r *RegistryDependency) parseRegistryPath() (string, string) {
 	return r.RegistryPath, ""
 }
 
+// Version extracts the semconv release tag from registry_path.
+// Example: "https://github.com/.../v1.38.0.zip[model]" -> "v1.38.0"
+func (r *RegistryDependency) Version() string {
+	zipURL, _ := r.parseRegistryPath()
+	return strings.TrimSuffix(path.Base(zipURL), ".zip")
+}
+
 func (r RepoInfo) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("name", r.Name),
@@ -210,3 +210,16 @@ func TestRepoInfoLogValue(t *testing.T) {
 		}
 	})
 }
+
+func TestRegistryDependencyVersion(t *testing.T) {
+	t.Run("Version - returns the semconv release tag", func(t *testing.T) {
+		semconv, err := NewRegistry().SemConv()
+		if err != nil {
+			t.Fatalf("SemConv() error = %v", err)
+		}
+
+		if got := semconv.Version(); got != "v1.38.0" {
+			t.Errorf("Version() = %q, want %q", got, "v1.38.0")
+		}
+	})
+}